@@ -0,0 +1,325 @@
+package botty
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// callbackDataLimit is the maximum size of callback_data in bytes.
+	callbackDataLimit = 64
+
+	callbackSeparator = "|"
+	callbackEscape    = '\\'
+
+	// callbackSignatureSize is the number of HMAC bytes kept in the callback data.
+	callbackSignatureSize = 8
+)
+
+var (
+	// ErrCallbackDataTooLong is returned when the encoded callback data exceeds 64 bytes.
+	ErrCallbackDataTooLong = errors.New("callback data exceeds 64 bytes")
+
+	// ErrInvalidCallbackData is returned when the callback data can't be decoded,
+	// or its signature doesn't match.
+	ErrInvalidCallbackData = errors.New("invalid callback data")
+)
+
+// WithCallbackSecret makes typed callbacks sign their data using HMAC-SHA256,
+// so buttons with the data changed by a client don't match the route.
+// The signature takes 12 bytes of the 64 bytes limit.
+func WithCallbackSecret(secret []byte) ClientOption {
+	return func(c *Client) {
+		c.callbackSecret = secret
+	}
+}
+
+// Callback encodes the fields of T into the callback data of buttons and
+// decodes them back for the handler registered by OnCallback.
+//
+// The data is the route prefix followed by the exported fields of T in the
+// order of declaration, separated by "|". Fields of the string, bool, integer
+// and float kinds are supported, integers are encoded in base 36 to save
+// space. A field with the `callback:"-"` tag is skipped.
+type Callback[T any] struct {
+	client *Client
+	prefix string
+}
+
+// OnCallback handles callback queries of the buttons built by the returned
+// Callback, the handler gets the decoded data. Callback queries with data that
+// can't be decoded don't match the route.
+//
+// OnCallback panics if the prefix is empty or contains "|" or "\", or T is not a
+// struct with fields of the supported kinds.
+func OnCallback[T any](c *Client, prefix string, f func(ctx *Context, data T) error, middleware ...Middleware) *Callback[T] {
+	if prefix == "" || strings.ContainsAny(prefix, callbackSeparator+string(callbackEscape)) {
+		panic(fmt.Sprintf("botty: invalid callback prefix %q", prefix))
+	}
+
+	if err := checkCallbackType(reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		panic(fmt.Sprintf("botty: %v", err))
+	}
+
+	cb := &Callback[T]{
+		client: c,
+		prefix: prefix,
+	}
+
+	c.addRoute(priorityQuery, func(ctx *Context) bool {
+		if ctx.update.CallbackQuery == nil || ctx.query != prefix {
+			return false
+		}
+
+		data, err := cb.decode(ctx.queryData)
+		if err != nil {
+			return false
+		}
+
+		ctx.callbackData = data
+
+		return true
+	}, func(ctx *Context) error {
+		return f(ctx, ctx.callbackData.(T))
+	}, middleware)
+
+	return cb
+}
+
+// Data encodes data into the callback data of a button. Data exceeding 64 bytes
+// is only allowed if the client has a callback store.
+func (cb *Callback[T]) Data(data T) (string, error) {
+	fields, err := encodeCallbackFields(reflect.ValueOf(data))
+	if err != nil {
+		return "", fmt.Errorf("can't encode callback data, %w", err)
+	}
+
+	s := cb.prefix
+	for _, field := range fields {
+		s += callbackSeparator + field
+	}
+
+	if cb.client.callbackSecret != nil {
+		s += callbackSeparator + cb.sign(s)
+	}
+
+	if len(s) > callbackDataLimit && cb.client.callbackStore == nil {
+		return "", fmt.Errorf("can't encode callback data of %d bytes, %w", len(s), ErrCallbackDataTooLong)
+	}
+
+	return s, nil
+}
+
+// Button returns a button with the text and the encoded data.
+func (cb *Callback[T]) Button(text string, data T) (InlineKeyboardButton, error) {
+	s, err := cb.Data(data)
+	if err != nil {
+		return InlineKeyboardButton{}, err
+	}
+
+	return InlineKeyboardButton{
+		Text:         text,
+		CallbackData: s,
+	}, nil
+}
+
+// decode decodes the whole callback data, including the prefix.
+func (cb *Callback[T]) decode(s string) (T, error) {
+	var data T
+
+	fields := splitCallbackFields(s)
+	if len(fields) == 0 || fields[0] != cb.prefix {
+		return data, ErrInvalidCallbackData
+	}
+
+	fields = fields[1:]
+
+	if cb.client.callbackSecret != nil {
+		i := strings.LastIndex(s, callbackSeparator)
+		if i < 0 || !hmac.Equal([]byte(s[i+1:]), []byte(cb.sign(s[:i]))) {
+			return data, ErrInvalidCallbackData
+		}
+
+		fields = fields[:len(fields)-1]
+	}
+
+	if err := decodeCallbackFields(reflect.ValueOf(&data).Elem(), fields); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+func (cb *Callback[T]) sign(s string) string {
+	mac := hmac.New(sha256.New, cb.client.callbackSecret)
+	mac.Write([]byte(s))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:callbackSignatureSize])
+}
+
+func checkCallbackType(t reflect.Type) error {
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("callback data type %s is not a struct", t)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !isCallbackField(f) {
+			continue
+		}
+
+		switch f.Type.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return fmt.Errorf("callback data field %s.%s has unsupported type %s", t, f.Name, f.Type)
+		}
+	}
+
+	return nil
+}
+
+func isCallbackField(f reflect.StructField) bool {
+	return f.IsExported() && f.Tag.Get("callback") != "-"
+}
+
+func encodeCallbackFields(v reflect.Value) ([]string, error) {
+	if err := checkCallbackType(v.Type()); err != nil {
+		return nil, err
+	}
+
+	var fields []string
+
+	for i := 0; i < v.NumField(); i++ {
+		if !isCallbackField(v.Type().Field(i)) {
+			continue
+		}
+
+		var s string
+
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			s = escapeCallbackField(f.String())
+		case reflect.Bool:
+			s = "0"
+			if f.Bool() {
+				s = "1"
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(f.Int(), 36)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(f.Uint(), 36)
+		case reflect.Float32, reflect.Float64:
+			s = strconv.FormatFloat(f.Float(), 'g', -1, f.Type().Bits())
+		}
+
+		fields = append(fields, s)
+	}
+
+	return fields, nil
+}
+
+func decodeCallbackFields(v reflect.Value, fields []string) error {
+	n := 0
+
+	for i := 0; i < v.NumField(); i++ {
+		if !isCallbackField(v.Type().Field(i)) {
+			continue
+		}
+
+		if n == len(fields) {
+			return ErrInvalidCallbackData
+		}
+
+		s := fields[n]
+		n++
+
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			f.SetString(s)
+		case reflect.Bool:
+			switch s {
+			case "0":
+				f.SetBool(false)
+			case "1":
+				f.SetBool(true)
+			default:
+				return ErrInvalidCallbackData
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			x, err := strconv.ParseInt(s, 36, f.Type().Bits())
+			if err != nil {
+				return ErrInvalidCallbackData
+			}
+			f.SetInt(x)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			x, err := strconv.ParseUint(s, 36, f.Type().Bits())
+			if err != nil {
+				return ErrInvalidCallbackData
+			}
+			f.SetUint(x)
+		case reflect.Float32, reflect.Float64:
+			x, err := strconv.ParseFloat(s, f.Type().Bits())
+			if err != nil {
+				return ErrInvalidCallbackData
+			}
+			f.SetFloat(x)
+		}
+	}
+
+	if n != len(fields) {
+		return ErrInvalidCallbackData
+	}
+
+	return nil
+}
+
+// escapeCallbackField escapes the separator and the escape character in s.
+func escapeCallbackField(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == callbackSeparator[0] || s[i] == callbackEscape {
+			b.WriteByte(callbackEscape)
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// splitCallbackFields splits s by the separators that are not escaped and
+// unescapes the fields. An empty s has no fields.
+func splitCallbackFields(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var (
+		fields []string
+		b      strings.Builder
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == callbackEscape && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case s[i] == callbackSeparator[0]:
+			fields = append(fields, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return append(fields, b.String())
+}
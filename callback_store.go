@@ -0,0 +1,178 @@
+package botty
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// storedCallbackPrefix marks the callback data replaced by a key of the callback store.
+	storedCallbackPrefix = "~:"
+
+	// storedCallbackKeySize is the number of random bytes in a callback store key.
+	storedCallbackKeySize = 12
+
+	defaultCallbackTTL = 24 * time.Hour
+
+	// minStoreSweep is the number of entries of the memory store after which
+	// the expired ones are dropped.
+	minStoreSweep = 1024
+)
+
+// CallbackStore keeps the callback data that doesn't fit into the 64 bytes
+// Telegram allows. Get returns false if there is no data under the key, e.g.
+// because it has expired.
+type CallbackStore interface {
+	Set(ctx context.Context, key, data string, ttl time.Duration) error
+	Get(ctx context.Context, key string) (string, bool, error)
+}
+
+// WithCallbackStore makes the client keep the callback data of inline buttons
+// exceeding 64 bytes in the store for the ttl, the button is sent with a short
+// key instead. The data is restored before the callback query is routed, so
+// handlers get the original data. Callback queries of the buttons with the
+// expired data go to the fallback handler.
+func WithCallbackStore(store CallbackStore, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl <= 0 {
+			ttl = defaultCallbackTTL
+		}
+
+		c.callbackStore = store
+		c.callbackTTL = ttl
+	}
+}
+
+type memoryCallbackEntry struct {
+	data    string
+	expires time.Time
+}
+
+type memoryCallbackStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryCallbackEntry
+	sweepAt int
+}
+
+// NewMemoryCallbackStore returns a callback store that keeps the data in memory,
+// so the data is lost on restart. Use a shared store, e.g. backed by Redis,
+// to keep the buttons working across restarts or several bot instances.
+func NewMemoryCallbackStore() CallbackStore {
+	return &memoryCallbackStore{
+		entries: make(map[string]memoryCallbackEntry),
+		sweepAt: minStoreSweep,
+	}
+}
+
+func (s *memoryCallbackStore) Set(_ context.Context, key, data string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	if len(s.entries) >= s.sweepAt {
+		s.dropExpired(now)
+
+		s.sweepAt = 2 * len(s.entries)
+		if s.sweepAt < minStoreSweep {
+			s.sweepAt = minStoreSweep
+		}
+	}
+
+	s.entries[key] = memoryCallbackEntry{
+		data:    data,
+		expires: now.Add(ttl),
+	}
+
+	return nil
+}
+
+func (s *memoryCallbackStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+
+	if time.Now().After(e.expires) {
+		delete(s.entries, key)
+		return "", false, nil
+	}
+
+	return e.data, true, nil
+}
+
+func (s *memoryCallbackStore) dropExpired(now time.Time) {
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// storeCallbackData returns a copy of the markup with the callback data
+// exceeding 64 bytes replaced by the keys of the callback store.
+func (c *Client) storeCallbackData(ctx context.Context, m *InlineKeyboardMarkup) (*InlineKeyboardMarkup, error) {
+	stored := &InlineKeyboardMarkup{
+		InlineKeyboardMarkup: make([][]InlineKeyboardButton, len(m.InlineKeyboardMarkup)),
+	}
+
+	for i, row := range m.InlineKeyboardMarkup {
+		stored.InlineKeyboardMarkup[i] = make([]InlineKeyboardButton, len(row))
+
+		for j, button := range row {
+			if len(button.CallbackData) > callbackDataLimit {
+				key, err := newCallbackKey()
+				if err != nil {
+					return nil, err
+				}
+
+				if err := c.callbackStore.Set(ctx, key, button.CallbackData, c.callbackTTL); err != nil {
+					return nil, fmt.Errorf("can't store callback data, %w", err)
+				}
+
+				button.CallbackData = storedCallbackPrefix + key
+			}
+
+			stored.InlineKeyboardMarkup[i][j] = button
+		}
+	}
+
+	return stored, nil
+}
+
+// loadCallbackData restores the callback data replaced by a key of the
+// callback store. The data is left as is if it has expired.
+func (c *Client) loadCallbackData(ctx context.Context, q *CallbackQuery) error {
+	if c.callbackStore == nil || !strings.HasPrefix(q.Data, storedCallbackPrefix) {
+		return nil
+	}
+
+	data, ok, err := c.callbackStore.Get(ctx, strings.TrimPrefix(q.Data, storedCallbackPrefix))
+	if err != nil {
+		return fmt.Errorf("can't load callback data, %w", err)
+	}
+
+	if ok {
+		q.Data = data
+	}
+
+	return nil
+}
+
+func newCallbackKey() (string, error) {
+	b := make([]byte, storedCallbackKeySize)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("can't generate callback key, %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,208 @@
+package botty
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testCallback struct {
+	ID     int
+	Name   string
+	Done   bool
+	Score  float64
+	Count  uint16
+	Secret string `callback:"-"`
+}
+
+func TestCallbackRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data testCallback
+	}{
+		{name: "zero", data: testCallback{}},
+		{name: "plain", data: testCallback{ID: 42, Name: "apple", Done: true, Score: 1.5, Count: 7}},
+		{name: "negative int", data: testCallback{ID: -1295, Score: -0.25}},
+		{name: "separator", data: testCallback{Name: "a|b||c"}},
+		{name: "escape", data: testCallback{Name: `a\b\`}},
+		{name: "escaped separator", data: testCallback{Name: `\|`}},
+		{name: "unicode", data: testCallback{Name: "яблоко 🍎"}},
+	}
+
+	for _, secret := range []string{"", "secret"} {
+		c := NewClient("token")
+		if secret != "" {
+			c = NewClient("token", WithCallbackSecret([]byte(secret)))
+		}
+		cb := OnCallback(c, "item", func(*Context, testCallback) error { return nil })
+
+		for _, tt := range tests {
+			t.Run(tt.name+"/secret="+secret, func(t *testing.T) {
+				s, err := cb.Data(tt.data)
+				if err != nil {
+					t.Fatalf("Data() error = %v", err)
+				}
+
+				got, err := cb.decode(s)
+				if err != nil {
+					t.Fatalf("decode(%q) error = %v", s, err)
+				}
+
+				if got != tt.data {
+					t.Errorf("decode(%q) = %+v, want %+v", s, got, tt.data)
+				}
+			})
+		}
+	}
+}
+
+func TestCallbackSkipsTaggedField(t *testing.T) {
+	c := NewClient("token")
+	cb := OnCallback(c, "item", func(*Context, testCallback) error { return nil })
+
+	s, err := cb.Data(testCallback{ID: 1, Secret: "hidden"})
+	if err != nil {
+		t.Fatalf("Data() error = %v", err)
+	}
+
+	if strings.Contains(s, "hidden") {
+		t.Errorf("Data() = %q, contains the skipped field", s)
+	}
+}
+
+func TestCallbackDecodeInvalid(t *testing.T) {
+	c := NewClient("token")
+	cb := OnCallback(c, "item", func(*Context, testCallback) error { return nil })
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "other prefix", data: "other|1|a|1|0|0"},
+		{name: "too few fields", data: "item|1|a|1|0"},
+		{name: "too many fields", data: "item|1|a|1|0|0|0"},
+		{name: "escaped separator shifts fields", data: `item|1|a\|1|0|0`},
+		{name: "invalid int", data: "item|!|a|1|0|0"},
+		{name: "invalid bool", data: "item|1|a|yes|0|0"},
+		{name: "negative uint", data: "item|1|a|1|0|-1"},
+		{name: "uint overflow", data: "item|1|a|1|0|zzzz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := cb.decode(tt.data); !errors.Is(err, ErrInvalidCallbackData) {
+				t.Errorf("decode(%q) error = %v, want %v", tt.data, err, ErrInvalidCallbackData)
+			}
+		})
+	}
+}
+
+func TestCallbackDecodeTampered(t *testing.T) {
+	c := NewClient("token", WithCallbackSecret([]byte("secret")))
+	cb := OnCallback(c, "item", func(*Context, testCallback) error { return nil })
+
+	s, err := cb.Data(testCallback{ID: 42, Name: "apple"})
+	if err != nil {
+		t.Fatalf("Data() error = %v", err)
+	}
+
+	i := strings.LastIndex(s, callbackSeparator)
+	signed, signature := s[:i], s[i+1:]
+
+	other := NewClient("token", WithCallbackSecret([]byte("other")))
+	otherCB := OnCallback(other, "item", func(*Context, testCallback) error { return nil })
+
+	forged, err := otherCB.Data(testCallback{ID: 42, Name: "apple"})
+	if err != nil {
+		t.Fatalf("Data() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "changed field", data: strings.Replace(signed, "apple", "apply", 1) + callbackSeparator + signature},
+		{name: "changed signature", data: signed + callbackSeparator + strings.Repeat("A", len(signature))},
+		{name: "no signature", data: signed},
+		{name: "other secret", data: forged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := cb.decode(tt.data); !errors.Is(err, ErrInvalidCallbackData) {
+				t.Errorf("decode(%q) error = %v, want %v", tt.data, err, ErrInvalidCallbackData)
+			}
+		})
+	}
+}
+
+func TestCallbackDataTooLong(t *testing.T) {
+	c := NewClient("token")
+	cb := OnCallback(c, "item", func(*Context, testCallback) error { return nil })
+
+	_, err := cb.Data(testCallback{Name: strings.Repeat("a", callbackDataLimit)})
+	if !errors.Is(err, ErrCallbackDataTooLong) {
+		t.Errorf("Data() error = %v, want %v", err, ErrCallbackDataTooLong)
+	}
+}
+
+func TestSplitCallbackFields(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: "a", want: []string{"a"}},
+		{in: "a|b", want: []string{"a", "b"}},
+		{in: "a||b|", want: []string{"a", "", "b", ""}},
+		{in: `a\|b|c`, want: []string{"a|b", "c"}},
+		{in: `a\\|b`, want: []string{`a\`, "b"}},
+		{in: `a\`, want: []string{`a\`}},
+	}
+
+	for _, tt := range tests {
+		got := splitCallbackFields(tt.in)
+		if !equalStrings(got, tt.want) {
+			t.Errorf("splitCallbackFields(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeCallbackField(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "abc", want: "abc"},
+		{in: "a|b", want: `a\|b`},
+		{in: `a\b`, want: `a\\b`},
+		{in: `|\`, want: `\|\\`},
+	}
+
+	for _, tt := range tests {
+		got := escapeCallbackField(tt.in)
+		if got != tt.want {
+			t.Errorf("escapeCallbackField(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+
+		if fields := splitCallbackFields(got); tt.in != "" && !equalStrings(fields, []string{tt.in}) {
+			t.Errorf("splitCallbackFields(%q) = %q, want %q", got, fields, []string{tt.in})
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,19 +1,25 @@
 package botty
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"path"
-	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
-	baseUrl        = "api.telegram.org"
+	defaultBaseURL = "https://api.telegram.org"
 	basePathPrefix = "bot"
+	filePathPrefix = "file"
+
+	// requestTimeoutMargin is added to the long polling timeout to get the
+	// deadline of a poll, so a stalled connection doesn't block polling forever.
+	requestTimeoutMargin = 10 * time.Second
 )
 
 const (
@@ -22,6 +28,18 @@ const (
 	methodEditMessageText     = "editMessageText"
 	methodAnswerCallbackQuery = "answerCallbackQuery"
 	methodSendPhoto           = "sendPhoto"
+	methodSendDocument        = "sendDocument"
+	methodSendAudio           = "sendAudio"
+	methodSendVideo           = "sendVideo"
+	methodSendAnimation       = "sendAnimation"
+	methodSendVoice           = "sendVoice"
+	methodSendVideoNote       = "sendVideoNote"
+	methodSetWebhook          = "setWebhook"
+	methodDeleteWebhook       = "deleteWebhook"
+	methodGetWebhookInfo      = "getWebhookInfo"
+	methodGetFile             = "getFile"
+	methodDeleteMessage       = "deleteMessage"
+	methodGetMe               = "getMe"
 )
 
 const (
@@ -30,24 +48,71 @@ const (
 	ParseModeHTML       = "HTML"
 )
 
+// ReplyMarkup is the reply_markup of a message: an inline keyboard, a reply
+// keyboard, ForceReply or ReplyKeyboardRemove.
 type ReplyMarkup interface {
-	GetText() string
+	json.Marshaler
+}
+
+// ReplyMarkupValidator is implemented by the markups that check their layout
+// before the request is sent, so an invalid markup fails without a network call.
+type ReplyMarkupValidator interface {
+	Validate() error
 }
 
 type Client struct {
-	client       http.Client
-	commands     map[string]func(u Update) error
-	messages     map[string]func(u Update) error
-	queries      map[string]func(u Update) error
-	token        string
-	host         string
-	basePath     string
-	offset       int
-	errorHandler func(error)
+	client           *http.Client
+	routes           []route
+	fallback         HandlerFunc
+	middleware       []Middleware
+	token            string
+	baseURL          string
+	offsets          offsetTracker
+	queries          queryTracker
+	pollLimit        int
+	pollTimeout      time.Duration
+	allowedUpdates   []string
+	minPollBackoff   time.Duration
+	maxPollBackoff   time.Duration
+	shutdownTimeout  time.Duration
+	workers          int
+	queueSize        int
+	queueFullHandler func(Update)
+	retryPolicy      *RetryPolicy
+	rateLimiter      RateLimiter
+	webhookSecret    string
+	callbackSecret   []byte
+	callbackStore    CallbackStore
+	callbackTTL      time.Duration
+	username         string
+	usernameMu       sync.Mutex
+	errorHandler     func(error)
 }
 
 type ClientOption func(*Client)
 
+// WithBaseURL sets the URL of the Bot API server, e.g. "http://localhost:8081"
+// for a self-hosted telegram-bot-api server. The URL may contain a path prefix.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests, e.g. to configure
+// timeouts, a proxy or a custom transport. The client timeout must exceed
+// the long polling timeout. A nil client is ignored.
+//
+// The default client has no total timeout, so large uploads and downloads
+// aren't cut off, polls time out 10 seconds after the long polling timeout.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
 func WithErrorHandler(handler func(err error)) ClientOption {
 	return func(c *Client) {
 		c.errorHandler = handler
@@ -56,13 +121,16 @@ func WithErrorHandler(handler func(err error)) ClientOption {
 
 func NewClient(token string, options ...ClientOption) *Client {
 	c := &Client{
-		client:   http.Client{},
-		commands: make(map[string]func(Update) error),
-		messages: make(map[string]func(Update) error),
-		queries:  make(map[string]func(Update) error),
-		token:    token,
-		host:     baseUrl,
-		basePath: basePathPrefix + token,
+		client:          &http.Client{},
+		token:           token,
+		baseURL:         defaultBaseURL,
+		pollLimit:       defaultPollLimit,
+		pollTimeout:     defaultPollTimeout,
+		minPollBackoff:  defaultMinPollBackoff,
+		maxPollBackoff:  defaultMaxPollBackoff,
+		shutdownTimeout: defaultShutdownTimeout,
+		workers:         defaultWorkers,
+		queueSize:       defaultQueueSize,
 	}
 
 	for _, o := range options {
@@ -72,269 +140,150 @@ func NewClient(token string, options ...ClientOption) *Client {
 	return c
 }
 
+// Run receives updates using long polling and dispatches them to the registered
+// handlers. It is equivalent to RunContext with a context that is never done.
 func (c *Client) Run() error {
-	for {
-		updates, err := c.getUpdates()
-		if err != nil {
-			return err
-		}
-		if len(updates) == 0 {
-			continue
-		}
-
-		for _, u := range updates {
-			if err := c.processUpdate(u); err != nil {
-				if c.errorHandler != nil {
-					c.errorHandler(err)
-					continue
-				}
-
-				return err
-			}
-		}
-	}
+	return c.RunContext(context.Background())
 }
 
-func (c *Client) OnCommands(commands []string, f func(Update) error) {
-	for _, cmd := range commands {
-		c.commands[cmd] = f
-	}
-}
+// RunContext receives updates using long polling and dispatches them to the
+// registered handlers until ctx is done. Failed polls are reported to the error
+// handler and retried with an exponential backoff.
+//
+// Once ctx is done, RunContext stops polling, waits up to the shutdown timeout
+// for the queued and in-flight updates and confirms the processed ones, so they
+// are not received again on the next start. It returns nil after a graceful
+// shutdown. If the bot token is rejected, or a handler fails and no error
+// handler is set, RunContext shuts down the same way and returns the error.
+func (c *Client) RunContext(ctx context.Context) error {
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	defer stopPolling()
 
-func (c *Client) OnCommand(cmd string, f func(Update) error) {
-	c.commands[cmd] = f
-}
-
-func (c *Client) OnMessages(messages []string, f func(Update) error) {
-	for _, msg := range messages {
-		c.messages[msg] = f
-	}
-}
+	handlerCtx, cancelHandlers := context.WithCancel(context.Background())
+	defer cancelHandlers()
 
-func (c *Client) OnMessage(msg string, f func(Update) error) {
-	c.messages[msg] = f
-}
+	c.offsets.reset()
 
-func (c *Client) OnQuery(query string, f func(Update) error) {
-	c.queries[query] = f
-}
+	d := newDispatcher(handlerCtx, c)
+	polling := make(chan error, 1)
 
-func (c *Client) processCommand(u Update) (bool, error) {
-	if !u.hasMessageText() {
-		return false, nil
-	}
+	go func() {
+		polling <- c.poll(pollCtx, d)
+	}()
 
-	cmd := c.parseCommand(u.Message.Text)
-	if cmd == "" {
-		return false, nil
-	}
+	var err error
 
-	if fn, ok := c.commands[cmd]; ok {
-		if err := fn(u); err != nil {
-			return false, fmt.Errorf("can't process command, %w", err)
-		}
+	select {
+	case err = <-polling:
+	case err = <-d.failures():
+		stopPolling()
+		<-polling
+	case <-ctx.Done():
+		<-polling
 	}
 
-	return true, nil
-}
+	timer := time.NewTimer(c.shutdownTimeout)
+	defer timer.Stop()
 
-func (c *Client) processMessage(u Update) (bool, error) {
-	if !u.hasMessageText() {
-		return false, nil
-	}
-
-	if fn, ok := c.messages["*"]; ok {
-		if err := fn(u); err != nil {
-			return false, err
-		}
+	select {
+	case <-d.close():
+	case <-timer.C:
+		cancelHandlers()
+		c.handleError(fmt.Errorf("in-flight handlers didn't finish within %s", c.shutdownTimeout))
 	}
 
-	msg := c.parseMessage(u.Message.Text)
+	commitCtx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+	defer cancel()
 
-	if fn, ok := c.messages[msg]; ok {
-		if err := fn(u); err != nil {
-			return false, err
-		}
+	if commitErr := c.commitOffset(commitCtx); commitErr != nil && err == nil {
+		err = commitErr
 	}
 
-	return true, nil
+	return err
 }
 
-func (c *Client) processQuery(u Update) (bool, error) {
-	if fn, ok := c.queries["*"]; ok {
-		if err := fn(u); err != nil {
-			return false, err
-		}
-	}
-
-	if u.CallbackQuery.Data != "" {
-		items := strings.Split(u.CallbackQuery.Data, "|")
-
-		if len(items) > 0 {
-			u.CallbackQuery.Data = strings.Trim(u.CallbackQuery.Data, items[0]+"|")
-		}
-
-		if fn, ok := c.queries[items[0]]; ok {
-			if err := fn(u); err != nil {
-				return false, err
-			}
-		}
-	}
-
-	return true, nil
+func (c *Client) methodURL(method string) string {
+	return c.baseURL + "/" + path.Join(basePathPrefix+c.token, method)
 }
 
-func (c *Client) processUpdate(u Update) error {
-	processed, err := c.processCommand(u)
-	if err != nil {
-		return err
-	}
-	if processed {
-		return nil
-	}
+// doRequest sends params as a JSON body. The chatID is the chat the request is
+// sent to, or zero if the request is not sent to a chat.
+func (c *Client) doRequest(ctx context.Context, method string, chatID int, params interface{}) ([]byte, error) {
+	u := c.methodURL(method)
 
-	processed, err = c.processMessage(u)
+	data, err := json.Marshal(params)
 	if err != nil {
-		return err
-	}
-	if processed {
-		return nil
+		return nil, fmt.Errorf("doRequest() - can't encode request, %w", err)
 	}
 
-	processed, err = c.processQuery(u)
-	if err != nil {
-		return err
-	}
-	if processed {
-		return c.replyToQuery(u)
-	}
-
-	return nil
-}
-
-func (c *Client) getUpdates() ([]Update, error) {
-	updates, err := c.updates(c.offset, 100)
-	if err != nil {
-		return nil, fmt.Errorf("an error occurred while receiving updates, %w", err)
-	}
-	if !updates.OK {
-		return nil, fmt.Errorf("can't get updates, %s", updates.Description)
-	}
-
-	if len(updates.Result) == 0 {
-		return make([]Update, 0), nil
-	}
-
-	c.offset = updates.Result[len(updates.Result)-1].UpdateID + 1
-
-	return updates.Result, nil
-}
-
-func (c *Client) parseCommand(cmd string) string {
-	cmd = strings.TrimSpace(cmd)
-
-	cmd = strings.ToLower(cmd)
-	if cmd == "" {
-		return ""
-	}
-
-	if cmd[0:1] != "/" {
-		return ""
-	}
-
-	return cmd
-}
-
-func (c *Client) parseMessage(msg string) string {
-	return strings.TrimSpace(msg)
-}
-
-func (c *Client) updates(offset, limit int) (UpdateResponse, error) {
-	q := url.Values{}
+	body, err := c.do(ctx, method, chatID, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
 
-	q.Add("offset", strconv.Itoa(offset))
-	q.Add("limit", strconv.Itoa(limit))
+		req.Header.Add("Content-Type", "application/json")
 
-	data, err := c.doRequest(methodGetUpdates, q)
+		return req, nil
+	})
 	if err != nil {
-		return UpdateResponse{}, fmt.Errorf("can't get updates, %w", err)
+		return nil, fmt.Errorf("doRequest() - %w", err)
 	}
 
-	var res UpdateResponse
-
-	if err := json.Unmarshal(data, &res); err != nil {
-		return UpdateResponse{}, fmt.Errorf("can't get updates, %w", err)
-	}
-
-	return res, nil
+	return body, nil
 }
 
-func (c *Client) doRequest(method string, query url.Values) ([]byte, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.host,
-		Path:   path.Join(c.basePath, method),
-	}
+func (c *Client) doMultipartFormRequest(ctx context.Context, method string, chatID int, form MultipartForm) ([]byte, error) {
+	u := c.methodURL(method)
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("doRequest() - can't create request, %w", err)
-	}
+	// the form is read once, so every attempt sends the same body
+	data := form.Form().Bytes()
 
-	req.URL.RawQuery = query.Encode()
+	body, err := c.do(ctx, method, chatID, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("doRequest() - can't exec request, %w", err)
-	}
-	defer func() { _ = res.Body.Close() }()
+		req.Header.Add("Content-Type", form.FormDataContentType())
 
-	body, err := io.ReadAll(res.Body)
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("doRequest() - can't read response, %w", err)
+		return nil, fmt.Errorf("doMultipartFormRequest() - %w", err)
 	}
 
 	return body, nil
 }
 
-func (c *Client) doMultipartFormRequest(method string, form MultipartForm) ([]byte, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.host,
-		Path:   path.Join(c.basePath, method),
-	}
-
-	req, err := http.NewRequest(http.MethodPost, u.String(), form.Form())
-	if err != nil {
-		return nil, fmt.Errorf("doMultipartFormRequest() - can't create request, %w", err)
-	}
-
-	req.Header.Add("Content-Type", form.FormDataContentType())
+func (c *Client) processResponse(res []byte) (*Message, error) {
+	decodedRes := new(Response)
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("doMultipartFormRequest() - can't exec request, %w", err)
+	if err := json.Unmarshal(res, decodedRes); err != nil {
+		return nil, fmt.Errorf("can't decode response, %w", err)
 	}
-	defer func() { _ = res.Body.Close() }()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("doMultipartFormRequest() - can't read response, %w", err)
+	if !decodedRes.OK {
+		return nil, newAPIError(decodedRes.ErrorCode, decodedRes.Description, decodedRes.Parameters)
 	}
 
-	return body, nil
+	return decodedRes.Result, nil
 }
 
-func (c *Client) processResponse(res []byte) (*Message, error) {
-	decodedRes := new(Response)
+func (c *Client) decodeResult(res []byte, result interface{}) error {
+	decodedRes := new(ResultResponse)
 
 	if err := json.Unmarshal(res, decodedRes); err != nil {
-		return nil, fmt.Errorf("can't decode response, %w", err)
+		return fmt.Errorf("can't decode response, %w", err)
 	}
 
 	if !decodedRes.OK {
-		return nil, fmt.Errorf("code: %d, description: %s", decodedRes.ErrorCode, decodedRes.Description)
+		return newAPIError(decodedRes.ErrorCode, decodedRes.Description, decodedRes.Parameters)
 	}
 
-	return decodedRes.Result, nil
+	if err := json.Unmarshal(decodedRes.Result, result); err != nil {
+		return fmt.Errorf("can't decode result, %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,207 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+const (
+	entityTypeBotCommand = "bot_command"
+	startCommand         = "/start"
+)
+
+type getMeRequest struct{}
+
+func (c *Client) GetMe() (*User, error) {
+	return c.GetMeContext(context.Background())
+}
+
+func (c *Client) GetMeContext(ctx context.Context) (_ *User, err error) {
+	defer func() { err = wrapIfErr("can't get me", err) }()
+
+	res, err := c.doRequest(ctx, methodGetMe, 0, &getMeRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	u := new(User)
+
+	if err := c.decodeResult(res, u); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// WithUsername sets the bot username used to check commands like "/start@MyBot",
+// so the client doesn't have to request it using GetMe.
+func WithUsername(username string) ClientOption {
+	return func(c *Client) {
+		c.username = strings.TrimPrefix(username, "@")
+	}
+}
+
+// botUsername returns the username of the bot, it is requested using GetMe
+// until a request succeeds. The lock isn't held during the request, so a slow
+// request doesn't block the other workers, at worst they request it too.
+func (c *Client) botUsername(ctx context.Context) (string, error) {
+	c.usernameMu.Lock()
+	username := c.username
+	c.usernameMu.Unlock()
+
+	if username != "" {
+		return username, nil
+	}
+
+	me, err := c.GetMeContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.usernameMu.Lock()
+	defer c.usernameMu.Unlock()
+
+	c.username = me.Username
+
+	return c.username, nil
+}
+
+// command is a bot command parsed from a message.
+type command struct {
+	name    string
+	mention string
+	args    string
+}
+
+// parseCommand parses the command starting the message, using the bot_command
+// entity if the message has entities. The command name is lowercased and the
+// "@username" suffix is split into the mention.
+func parseCommand(m *Message) (command, bool) {
+	text := m.Text
+	name, args := "", ""
+
+	if len(m.MessageEntities) > 0 {
+		e := m.MessageEntities[0]
+		if e.Type != entityTypeBotCommand || e.Offset != 0 {
+			return command{}, false
+		}
+
+		encoded := utf16.Encode([]rune(text))
+		if e.Length > len(encoded) {
+			return command{}, false
+		}
+
+		name = string(utf16.Decode(encoded[:e.Length]))
+		args = string(utf16.Decode(encoded[e.Length:]))
+	} else {
+		text = strings.TrimSpace(text)
+		if !strings.HasPrefix(text, "/") {
+			return command{}, false
+		}
+
+		name = text
+		if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+			name, args = text[:i], text[i:]
+		}
+	}
+
+	name, mention, _ := strings.Cut(name, "@")
+
+	return command{
+		name:    strings.ToLower(name),
+		mention: mention,
+		args:    strings.TrimSpace(args),
+	}, true
+}
+
+// splitArgs splits the arguments like a shell does: by spaces, keeping quoted
+// strings together and unescaping characters preceded by a backslash.
+func splitArgs(args string) []string {
+	var (
+		result  []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range args {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				result = append(result, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		result = append(result, current.String())
+	}
+
+	return result
+}
+
+// Command returns the lowercased command without the "@username" suffix,
+// e.g. "/start", or an empty string if the message is not a command.
+func (ctx *Context) Command() string {
+	return ctx.command.name
+}
+
+// Args returns the text following the command.
+func (ctx *Context) Args() string {
+	return ctx.command.args
+}
+
+// ArgsList returns the arguments following the command split like a shell
+// does, e.g. `/add "John Doe" 42` has the "John Doe" and "42" arguments.
+func (ctx *Context) ArgsList() []string {
+	return splitArgs(ctx.command.args)
+}
+
+// StartPayload returns the payload of a deep link like https://t.me/MyBot?start=payload,
+// which is sent as the argument of the /start command.
+func (ctx *Context) StartPayload() string {
+	if ctx.command.name != startCommand {
+		return ""
+	}
+
+	return ctx.command.args
+}
+
+// isForBot reports whether the command is addressed to the bot, commands
+// without the "@username" suffix are addressed to every bot in the chat.
+func (c *Client) isForBot(ctx context.Context, cmd command) (bool, error) {
+	if cmd.mention == "" {
+		return true, nil
+	}
+
+	username, err := c.botUsername(ctx)
+	if err != nil {
+		return false, fmt.Errorf("can't check command mention, %w", err)
+	}
+
+	return strings.EqualFold(cmd.mention, username), nil
+}
@@ -0,0 +1,125 @@
+package botty
+
+import (
+	"testing"
+	"unicode/utf16"
+)
+
+func commandEntity(cmd string) []MessageEntity {
+	return []MessageEntity{{
+		Type:   entityTypeBotCommand,
+		Offset: 0,
+		Length: len(utf16.Encode([]rune(cmd))),
+	}}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		entities []MessageEntity
+		want     command
+		wantOK   bool
+	}{
+		{
+			name:   "plain",
+			text:   "/start",
+			want:   command{name: "/start"},
+			wantOK: true,
+		},
+		{
+			name:   "args",
+			text:   "  /Add apple  42 ",
+			want:   command{name: "/add", args: "apple  42"},
+			wantOK: true,
+		},
+		{
+			name:   "mention",
+			text:   "/cmd@MyBot some args",
+			want:   command{name: "/cmd", mention: "MyBot", args: "some args"},
+			wantOK: true,
+		},
+		{
+			name:     "entity",
+			text:     "/cmd@MyBot some args",
+			entities: commandEntity("/cmd@MyBot"),
+			want:     command{name: "/cmd", mention: "MyBot", args: "some args"},
+			wantOK:   true,
+		},
+		{
+			name:     "entity with non-BMP args",
+			text:     "/cmd@MyBot 🍎🍐 x",
+			entities: commandEntity("/cmd@MyBot"),
+			want:     command{name: "/cmd", mention: "MyBot", args: "🍎🍐 x"},
+			wantOK:   true,
+		},
+		{
+			name:     "entity without separator",
+			text:     "/cmd🍎 x",
+			entities: commandEntity("/cmd"),
+			want:     command{name: "/cmd", args: "🍎 x"},
+			wantOK:   true,
+		},
+		{
+			name:   "not a command",
+			text:   "hello /start",
+			wantOK: false,
+		},
+		{
+			name:     "entity not at the start",
+			text:     "🍎 /start",
+			entities: []MessageEntity{{Type: entityTypeBotCommand, Offset: 3, Length: 6}},
+			wantOK:   false,
+		},
+		{
+			name:     "other entity first",
+			text:     "/start",
+			entities: []MessageEntity{{Type: "bold", Offset: 0, Length: 6}},
+			wantOK:   false,
+		},
+		{
+			name:     "entity past the text",
+			text:     "/start",
+			entities: []MessageEntity{{Type: entityTypeBotCommand, Offset: 0, Length: 10}},
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCommand(&Message{Text: tt.text, MessageEntities: tt.entities})
+			if ok != tt.wantOK {
+				t.Fatalf("parseCommand(%q) ok = %v, want %v", tt.text, ok, tt.wantOK)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseCommand(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: "   ", want: nil},
+		{in: "a b  c", want: []string{"a", "b", "c"}},
+		{in: `"John Doe" 42`, want: []string{"John Doe", "42"}},
+		{in: `'a "b"' c`, want: []string{`a "b"`, "c"}},
+		{in: `a\ b c`, want: []string{"a b", "c"}},
+		{in: `"a \" b"`, want: []string{`a " b`}},
+		{in: `'a\b'`, want: []string{`a\b`}},
+		{in: `""`, want: []string{""}},
+		{in: "🍎 \"🍐 🍋\"", want: []string{"🍎", "🍐 🍋"}},
+	}
+
+	for _, tt := range tests {
+		got := splitArgs(tt.in)
+		if !equalStrings(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
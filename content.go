@@ -0,0 +1,105 @@
+package botty
+
+// ContentType is the kind of the message content.
+type ContentType string
+
+const (
+	ContentText      ContentType = "text"
+	ContentAnimation ContentType = "animation"
+	ContentAudio     ContentType = "audio"
+	ContentDocument  ContentType = "document"
+	ContentPhoto     ContentType = "photo"
+	ContentSticker   ContentType = "sticker"
+	ContentVideo     ContentType = "video"
+	ContentVideoNote ContentType = "video_note"
+	ContentVoice     ContentType = "voice"
+	ContentContact   ContentType = "contact"
+	ContentPoll      ContentType = "poll"
+	ContentVenue     ContentType = "venue"
+	ContentLocation  ContentType = "location"
+)
+
+// ContentType returns the kind of the message content, or an empty string if
+// the kind is unknown, e.g. for service messages.
+func (m *Message) ContentType() ContentType {
+	switch {
+	case m.Text != "":
+		return ContentText
+	// animations also have the document field set
+	case m.Animation != nil:
+		return ContentAnimation
+	case m.Audio != nil:
+		return ContentAudio
+	case m.Document != nil:
+		return ContentDocument
+	case len(m.Photo) > 0:
+		return ContentPhoto
+	case m.Sticker != nil:
+		return ContentSticker
+	case m.Video != nil:
+		return ContentVideo
+	case m.VideoNote != nil:
+		return ContentVideoNote
+	case m.Voice != nil:
+		return ContentVoice
+	case m.Contact != nil:
+		return ContentContact
+	case m.Poll != nil:
+		return ContentPoll
+	// venues also have the location field set
+	case m.Venue != nil:
+		return ContentVenue
+	case m.Location != nil:
+		return ContentLocation
+	}
+
+	return ""
+}
+
+// OnContent handles messages with the content of the given type, e.g. photos.
+// Content routes are tried after the text message routes.
+func (c *Client) OnContent(t ContentType, f HandlerFunc, middleware ...Middleware) {
+	c.addRoute(priorityContent, func(ctx *Context) bool {
+		return ctx.update.Message != nil && ctx.update.Message.ContentType() == t
+	}, f, middleware)
+}
+
+func (c *Client) OnAnimation(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentAnimation, f, middleware...)
+}
+
+func (c *Client) OnAudio(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentAudio, f, middleware...)
+}
+
+func (c *Client) OnDocument(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentDocument, f, middleware...)
+}
+
+func (c *Client) OnPhoto(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentPhoto, f, middleware...)
+}
+
+func (c *Client) OnSticker(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentSticker, f, middleware...)
+}
+
+func (c *Client) OnVideo(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentVideo, f, middleware...)
+}
+
+func (c *Client) OnVideoNote(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentVideoNote, f, middleware...)
+}
+
+func (c *Client) OnVoice(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentVoice, f, middleware...)
+}
+
+func (c *Client) OnContact(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentContact, f, middleware...)
+}
+
+func (c *Client) OnLocation(f HandlerFunc, middleware ...Middleware) {
+	c.OnContent(ContentLocation, f, middleware...)
+}
@@ -0,0 +1,170 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Context is passed to handlers, it wraps the update with helpers that work
+// for any update kind and carries values set by middleware.
+type Context struct {
+	ctx          context.Context
+	client       *Client
+	update       Update
+	command      command
+	query        string
+	queryData    string
+	callbackData interface{}
+	matches      []string
+	matchNames   []string
+	mu           sync.RWMutex
+	values       map[string]interface{}
+}
+
+func newContext(ctx context.Context, c *Client, u Update) *Context {
+	return &Context{
+		ctx:    ctx,
+		client: c,
+		update: u,
+	}
+}
+
+// Context returns the context of the update, it is done when the update
+// processing must stop, e.g. on shutdown.
+func (ctx *Context) Context() context.Context {
+	return ctx.ctx
+}
+
+// SetContext replaces the context of the update, e.g. to add a deadline in middleware.
+func (ctx *Context) SetContext(c context.Context) {
+	ctx.ctx = c
+}
+
+func (ctx *Context) Client() *Client {
+	return ctx.client
+}
+
+func (ctx *Context) Update() Update {
+	return ctx.update
+}
+
+// Chat returns the chat the update belongs to, or nil if there is none, e.g. for inline queries.
+func (ctx *Context) Chat() *Chat {
+	return ctx.update.chat()
+}
+
+// Sender returns the user who sent the update, or nil if there is none, e.g. for channel posts.
+func (ctx *Context) Sender() *User {
+	return ctx.update.sender()
+}
+
+// Message returns the message of the update, for callback queries it is the
+// message with the pressed button. It returns nil if there is no message.
+func (ctx *Context) Message() *Message {
+	return ctx.update.message()
+}
+
+// Text returns the text of the message, or an empty string if there is no message.
+func (ctx *Context) Text() string {
+	if m := ctx.Message(); m != nil {
+		return m.Text
+	}
+
+	return ""
+}
+
+// isTextMessage reports whether the update is a text message, but not a command.
+func (ctx *Context) isTextMessage() bool {
+	return ctx.update.hasMessageText() && ctx.command.name == ""
+}
+
+// Set stores a value for the rest of the update processing.
+func (ctx *Context) Set(key string, value interface{}) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if ctx.values == nil {
+		ctx.values = make(map[string]interface{})
+	}
+
+	ctx.values[key] = value
+}
+
+// Get returns the value stored by Set.
+func (ctx *Context) Get(key string) (interface{}, bool) {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+
+	value, ok := ctx.values[key]
+
+	return value, ok
+}
+
+// Reply sends a message to the chat of the update.
+func (ctx *Context) Reply(text string, options ...ReplyOption) error {
+	return ctx.client.ReplyContext(ctx.ctx, ctx.update, text, options...)
+}
+
+// Edit replaces the text of the message of the update, e.g. the message with
+// the pressed button. Only the parse mode, entities, web page preview and reply
+// markup options are applied.
+func (ctx *Context) Edit(text string, options ...ReplyOption) error {
+	m := &MessageData{}
+
+	for _, o := range options {
+		o(m)
+	}
+
+	data := &UpdateMessageData{
+		Text:                  text,
+		ParseMode:             m.ParseMode,
+		Entities:              m.Entities,
+		DisableWebPagePreview: m.DisableWebPagePreview,
+		ReplyMarkup:           m.ReplyMarkup,
+	}
+
+	if q := ctx.update.CallbackQuery; q != nil && q.InlineMessageID != "" {
+		data.InlineMessageID = q.InlineMessageID
+	} else if msg := ctx.Message(); msg != nil && msg.Chat != nil {
+		data.ChatID = msg.Chat.ID
+		data.MessageID = msg.MessageID
+	} else {
+		return fmt.Errorf("can't edit message, the update has no message")
+	}
+
+	_, err := ctx.client.UpdateMessageContext(ctx.ctx, data)
+
+	return err
+}
+
+// Delete deletes the message of the update.
+func (ctx *Context) Delete() error {
+	msg := ctx.Message()
+	if msg == nil || msg.Chat == nil {
+		return fmt.Errorf("can't delete message, the update has no message")
+	}
+
+	return ctx.client.DeleteMessageContext(ctx.ctx, msg.Chat.ID, msg.MessageID)
+}
+
+// Answer answers the callback query of the update, an empty text only stops
+// the progress bar on the button. Once a handler answered the query, the client
+// doesn't send the empty answer after the handlers.
+func (ctx *Context) Answer(text string, options ...AnswerOption) error {
+	q := ctx.update.CallbackQuery
+	if q == nil {
+		return fmt.Errorf("can't answer, the update has no callback query")
+	}
+
+	d := &AnswerCallbackQueryData{
+		CallbackQueryID: q.ID,
+		Text:            text,
+	}
+
+	for _, o := range options {
+		o(d)
+	}
+
+	return ctx.client.AnswerCallbackQueryContext(ctx.ctx, d)
+}
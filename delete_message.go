@@ -0,0 +1,41 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type deleteMessageRequest struct {
+	ChatID    int `json:"chat_id"`
+	MessageID int `json:"message_id"`
+}
+
+func (c *Client) DeleteMessage(chatID, messageID int) error {
+	return c.DeleteMessageContext(context.Background(), chatID, messageID)
+}
+
+func (c *Client) DeleteMessageContext(ctx context.Context, chatID, messageID int) (err error) {
+	defer func() { err = wrapIfErr("can't delete message", err) }()
+
+	if chatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if messageID == 0 {
+		return fmt.Errorf("message_id is required")
+	}
+
+	req := &deleteMessageRequest{
+		ChatID:    chatID,
+		MessageID: messageID,
+	}
+
+	res, err := c.doRequest(ctx, methodDeleteMessage, chatID, req)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+
+	return c.decodeResult(res, &ok)
+}
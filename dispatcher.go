@@ -0,0 +1,208 @@
+package botty
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultWorkers   = 1
+	defaultQueueSize = defaultPollLimit
+)
+
+// WithWorkers sets the number of workers processing updates received by Run.
+// Updates from the same chat are always processed by the same worker, so they
+// are handled in the order they were sent.
+func WithWorkers(workers int) ClientOption {
+	return func(c *Client) {
+		c.workers = workers
+	}
+}
+
+// WithQueueSize sets the number of updates waiting for each worker. Polling
+// blocks while the queue of a worker is full.
+func WithQueueSize(size int) ClientOption {
+	return func(c *Client) {
+		c.queueSize = size
+	}
+}
+
+// WithQueueFullHandler sets a hook called with an update that has to wait,
+// because the queue of its worker is full, e.g. to record a metric.
+func WithQueueFullHandler(handler func(u Update)) ClientOption {
+	return func(c *Client) {
+		c.queueFullHandler = handler
+	}
+}
+
+type dispatcher struct {
+	client *Client
+	ctx    context.Context
+	queues []chan Update
+	wg     sync.WaitGroup
+	failed atomic.Bool
+	errc   chan error
+}
+
+// newDispatcher starts the workers. Handlers are called with ctx.
+func newDispatcher(ctx context.Context, c *Client) *dispatcher {
+	workers := c.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	d := &dispatcher{
+		client: c,
+		ctx:    ctx,
+		queues: make([]chan Update, workers),
+		errc:   make(chan error, 1),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan Update, c.queueSize)
+
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+
+	return d
+}
+
+// dispatch queues the update, it blocks while the queue is full and returns
+// false if ctx is done before the update is queued.
+func (d *dispatcher) dispatch(ctx context.Context, u Update) bool {
+	q := d.queues[u.chatKey()%uint64(len(d.queues))]
+
+	select {
+	case q <- u:
+		return true
+	default:
+	}
+
+	if d.client.queueFullHandler != nil {
+		d.client.queueFullHandler(u)
+	}
+
+	select {
+	case q <- u:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// failures receives the first handler error when no error handler is set.
+func (d *dispatcher) failures() <-chan error {
+	return d.errc
+}
+
+// close stops accepting updates, the returned channel is closed once the
+// workers have processed the queued ones.
+func (d *dispatcher) close() <-chan struct{} {
+	for _, q := range d.queues {
+		close(q)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	return done
+}
+
+func (d *dispatcher) work(q <-chan Update) {
+	defer d.wg.Done()
+
+	for u := range q {
+		// the remaining updates stay unconfirmed, so they are received again on the next start
+		if d.failed.Load() || d.ctx.Err() != nil {
+			continue
+		}
+
+		if err := d.client.processUpdate(d.ctx, u); err != nil {
+			if d.client.errorHandler == nil {
+				d.fail(err)
+				continue
+			}
+
+			d.client.errorHandler(err)
+		}
+
+		d.client.offsets.done(u.UpdateID)
+	}
+}
+
+func (d *dispatcher) fail(err error) {
+	if d.failed.CompareAndSwap(false, true) {
+		d.errc <- err
+	}
+}
+
+// offsetTracker keeps track of received updates that are not processed yet.
+type offsetTracker struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]struct{}
+}
+
+func (t *offsetTracker) received(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending == nil {
+		t.pending = make(map[int]struct{})
+	}
+
+	t.pending[id] = struct{}{}
+	t.next = id + 1
+}
+
+// reset forgets the pending updates, so they are received again.
+func (t *offsetTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id := range t.pending {
+		if id < t.next {
+			t.next = id
+		}
+	}
+
+	t.pending = nil
+}
+
+func (t *offsetTracker) done(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.pending, id)
+}
+
+// fetchOffset returns the offset of the next poll.
+func (t *offsetTracker) fetchOffset() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.next
+}
+
+// commitOffset returns the offset that confirms every processed update
+// without confirming any pending one.
+func (t *offsetTracker) commitOffset() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offset := t.next
+
+	for id := range t.pending {
+		if id < offset {
+			offset = id
+		}
+	}
+
+	return offset
+}
@@ -0,0 +1,258 @@
+package botty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBotAPI serves getUpdates from a fixed list of updates and records the
+// offsets of the requests confirming them.
+type fakeBotAPI struct {
+	updates []Update
+
+	mu      sync.Mutex
+	commits []int
+}
+
+func newFakeBotAPI(t *testing.T, updates []Update) (*fakeBotAPI, *httptest.Server) {
+	api := &fakeBotAPI{updates: updates}
+
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+
+	return api, srv
+}
+
+func (api *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req getUpdatesRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res := UpdateResponse{OK: true, Result: []Update{}}
+
+	// commitOffset confirms the updates using a short poll of a single update
+	if req.Timeout == 0 {
+		api.mu.Lock()
+		api.commits = append(api.commits, req.Offset)
+		api.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	for _, u := range api.updates {
+		if u.UpdateID >= req.Offset && len(res.Result) < req.Limit {
+			res.Result = append(res.Result, u)
+		}
+	}
+
+	if len(res.Result) == 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (api *fakeBotAPI) committed() []int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return append([]int(nil), api.commits...)
+}
+
+func newTestClient(srv *httptest.Server, options ...ClientOption) *Client {
+	return NewClient("token", append([]ClientOption{
+		WithBaseURL(srv.URL),
+		WithShutdownTimeout(time.Second),
+	}, options...)...)
+}
+
+func textUpdate(id, chatID int) Update {
+	return Update{
+		UpdateID: id,
+		Message: &Message{
+			MessageID: id,
+			Text:      "hello",
+			Chat:      &Chat{ID: chatID, Type: "private"},
+		},
+	}
+}
+
+func TestDispatcherKeepsChatOrder(t *testing.T) {
+	const (
+		chats    = 3
+		perChat  = 20
+		workers  = 4
+		firstID  = 1
+		updateNo = chats * perChat
+	)
+
+	var updates []Update
+	for i := 0; i < updateNo; i++ {
+		updates = append(updates, textUpdate(firstID+i, 100+i%chats))
+	}
+
+	api, srv := newFakeBotAPI(t, updates)
+	c := newTestClient(srv, WithWorkers(workers), WithPollLimit(7))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		processed = make(map[int][]int)
+		count     int
+	)
+
+	c.Fallback(func(uctx *Context) error {
+		// later updates of other chats overtake the slow ones
+		time.Sleep(time.Duration(uctx.Update().UpdateID%3) * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		chatID := uctx.Chat().ID
+		processed[chatID] = append(processed[chatID], uctx.Update().UpdateID)
+
+		if count++; count == updateNo {
+			cancel()
+		}
+
+		return nil
+	})
+
+	if err := c.RunContext(ctx); err != nil {
+		t.Fatalf("RunContext() error = %v", err)
+	}
+
+	for chatID, ids := range processed {
+		if len(ids) != perChat {
+			t.Errorf("chat %d: got %d updates, want %d", chatID, len(ids), perChat)
+		}
+
+		for i := 1; i < len(ids); i++ {
+			if ids[i] < ids[i-1] {
+				t.Errorf("chat %d: updates processed out of order: %v", chatID, ids)
+				break
+			}
+		}
+	}
+
+	if got, want := api.committed(), []int{firstID + updateNo}; !equalInts(got, want) {
+		t.Errorf("committed offsets = %v, want %v", got, want)
+	}
+}
+
+func TestDispatcherDoesNotCommitFailedUpdate(t *testing.T) {
+	errHandler := errors.New("handler failed")
+
+	api, srv := newFakeBotAPI(t, []Update{
+		textUpdate(1, 100),
+		textUpdate(2, 100),
+		textUpdate(3, 100),
+		textUpdate(4, 100),
+		textUpdate(5, 100),
+	})
+	c := newTestClient(srv, WithWorkers(2))
+
+	c.Fallback(func(uctx *Context) error {
+		if uctx.Update().UpdateID == 3 {
+			return errHandler
+		}
+
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := c.RunContext(ctx)
+	if !errors.Is(err, errHandler) {
+		t.Fatalf("RunContext() error = %v, want %v", err, errHandler)
+	}
+
+	// update 3 and the updates after it are received again on the next start
+	if got, want := api.committed(), []int{3}; !equalInts(got, want) {
+		t.Errorf("committed offsets = %v, want %v", got, want)
+	}
+}
+
+func TestOffsetTrackerCommitOffset(t *testing.T) {
+	var tr offsetTracker
+
+	if got := tr.commitOffset(); got != 0 {
+		t.Fatalf("commitOffset() before any update = %d, want 0", got)
+	}
+
+	for id := 10; id <= 15; id++ {
+		tr.received(id)
+	}
+
+	if got := tr.fetchOffset(); got != 16 {
+		t.Errorf("fetchOffset() = %d, want 16", got)
+	}
+
+	tests := []struct {
+		done int
+		want int
+	}{
+		{done: 11, want: 10},
+		{done: 13, want: 10},
+		{done: 10, want: 12},
+		{done: 15, want: 12},
+		{done: 12, want: 14},
+		{done: 14, want: 16},
+	}
+
+	for _, tt := range tests {
+		tr.done(tt.done)
+
+		if got := tr.commitOffset(); got != tt.want {
+			t.Errorf("commitOffset() after done(%d) = %d, want %d", tt.done, got, tt.want)
+		}
+	}
+}
+
+func TestOffsetTrackerReset(t *testing.T) {
+	var tr offsetTracker
+
+	for id := 1; id <= 4; id++ {
+		tr.received(id)
+	}
+
+	tr.done(1)
+	tr.done(3)
+	tr.reset()
+
+	// the pending updates are received again
+	if got := tr.fetchOffset(); got != 2 {
+		t.Errorf("fetchOffset() after reset = %d, want 2", got)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,102 @@
 package botty
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ResponseParameters describes why a request was unsuccessful.
+// Doc https://core.telegram.org/bots/api#responseparameters
+type ResponseParameters struct {
+	MigrateToChatID int `json:"migrate_to_chat_id"`
+	RetryAfter      int `json:"retry_after"`
+}
+
+// APIError is returned when Telegram responds with "ok": false.
+// Use errors.As to get it from errors returned by the client methods.
+type APIError struct {
+	ErrorCode   int
+	Description string
+	Parameters  *ResponseParameters
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("code: %d, description: %s", e.ErrorCode, e.Description)
+}
+
+func newAPIError(code int, description string, parameters *ResponseParameters) *APIError {
+	return &APIError{
+		ErrorCode:   code,
+		Description: description,
+		Parameters:  parameters,
+	}
+}
+
+// IsBadRequest reports whether err is an APIError with the 400 code.
+func IsBadRequest(err error) bool {
+	return hasErrorCode(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized reports whether err is an APIError with the 401 code, e.g. the bot token is revoked.
+func IsUnauthorized(err error) bool {
+	return hasErrorCode(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with the 403 code, e.g. the bot was blocked by the user.
+func IsForbidden(err error) bool {
+	return hasErrorCode(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an APIError with the 404 code.
+func IsNotFound(err error) bool {
+	return hasErrorCode(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError with the 409 code, e.g. a webhook is set while polling.
+func IsConflict(err error) bool {
+	return hasErrorCode(err, http.StatusConflict)
+}
+
+// IsTooManyRequests reports whether err is an APIError with the 429 code,
+// RetryAfter returns how long to wait before repeating the request.
+func IsTooManyRequests(err error) bool {
+	return hasErrorCode(err, http.StatusTooManyRequests)
+}
+
+// IsChatMigrated reports whether err is an APIError caused by a group migrated
+// to a supergroup, MigrateToChatID returns the ID of the supergroup.
+func IsChatMigrated(err error) bool {
+	return MigrateToChatID(err) != 0
+}
+
+// RetryAfter returns the number of seconds to wait before repeating the request,
+// or zero if err is not an APIError with the retry_after parameter.
+func RetryAfter(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Parameters != nil {
+		return apiErr.Parameters.RetryAfter
+	}
+
+	return 0
+}
+
+// MigrateToChatID returns the ID of the supergroup the group was migrated to,
+// or zero if err is not an APIError with the migrate_to_chat_id parameter.
+func MigrateToChatID(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Parameters != nil {
+		return apiErr.Parameters.MigrateToChatID
+	}
+
+	return 0
+}
+
+func hasErrorCode(err error, code int) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == code
+}
 
 func wrapIfErr(msg string, err error) error {
 	if err == nil {
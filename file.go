@@ -0,0 +1,81 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+)
+
+type getFileRequest struct {
+	FileID string `json:"file_id"`
+}
+
+func (c *Client) GetFile(fileID string) (*File, error) {
+	return c.GetFileContext(context.Background(), fileID)
+}
+
+func (c *Client) GetFileContext(ctx context.Context, fileID string) (_ *File, err error) {
+	defer func() { err = wrapIfErr("can't get file", err) }()
+
+	if fileID == "" {
+		return nil, fmt.Errorf("file_id is required")
+	}
+
+	res, err := c.doRequest(ctx, methodGetFile, 0, &getFileRequest{FileID: fileID})
+	if err != nil {
+		return nil, err
+	}
+
+	f := new(File)
+
+	if err := c.decodeResult(res, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// FileURL returns the download URL of the file path returned by GetFile.
+func (c *Client) FileURL(filePath string) string {
+	return c.baseURL + "/" + path.Join(filePathPrefix, basePathPrefix+c.token, filePath)
+}
+
+// DownloadFile opens the file returned by GetFile. A self-hosted Bot API server
+// running in the local mode returns absolute paths on its own disk, such files
+// are opened directly, so the bot must share the file system with the server.
+func (c *Client) DownloadFile(f *File) (io.ReadCloser, error) {
+	return c.DownloadFileContext(context.Background(), f)
+}
+
+func (c *Client) DownloadFileContext(ctx context.Context, f *File) (_ io.ReadCloser, err error) {
+	defer func() { err = wrapIfErr("can't download file", err) }()
+
+	if f.FilePath == "" {
+		return nil, fmt.Errorf("file_path is required")
+	}
+
+	if path.IsAbs(f.FilePath) {
+		return os.Open(f.FilePath)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.FileURL(f.FilePath), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+
+		return nil, fmt.Errorf("unexpected status, %s", res.Status)
+	}
+
+	return res.Body, nil
+}
@@ -0,0 +1,102 @@
+package botty
+
+import "unicode/utf8"
+
+// maxRowButtons is the maximum number of buttons Telegram shows in a row.
+const maxRowButtons = 8
+
+// WithGrid lays the buttons out in rows of the given number of columns, the
+// last row has the remaining buttons.
+func WithGrid(columns int, buttons ...InlineKeyboardButton) func(markup *InlineKeyboardMarkup) {
+	return func(markup *InlineKeyboardMarkup) {
+		for _, row := range gridRows(columns, buttons) {
+			WithRow(row...)(markup)
+		}
+	}
+}
+
+// WithBalancedGrid lays the buttons out in as few rows as WithGrid does, but
+// spreads them evenly, e.g. 7 buttons in 3 columns make rows of 3, 2 and 2
+// buttons instead of 3, 3 and 1.
+func WithBalancedGrid(columns int, buttons ...InlineKeyboardButton) func(markup *InlineKeyboardMarkup) {
+	return func(markup *InlineKeyboardMarkup) {
+		cols := clampColumns(columns)
+		rows := (len(buttons) + cols - 1) / cols
+		rest := buttons
+
+		for i := 0; i < rows; i++ {
+			// the first len(buttons) % rows rows take one button more
+			n := len(rest) / (rows - i)
+			if len(rest)%(rows-i) != 0 {
+				n++
+			}
+
+			WithRow(rest[:n]...)(markup)
+			rest = rest[n:]
+		}
+	}
+}
+
+// WithWidthLayout fills each row with buttons while the total length of their
+// labels fits into width characters, so long labels get a row of their own.
+func WithWidthLayout(width int, buttons ...InlineKeyboardButton) func(markup *InlineKeyboardMarkup) {
+	return func(markup *InlineKeyboardMarkup) {
+		var (
+			row      []InlineKeyboardButton
+			rowWidth int
+		)
+
+		for _, b := range buttons {
+			w := utf8.RuneCountInString(b.Text)
+
+			if len(row) > 0 && (rowWidth+w > width || len(row) == maxRowButtons) {
+				WithRow(row...)(markup)
+				row, rowWidth = nil, 0
+			}
+
+			row = append(row, b)
+			rowWidth += w
+		}
+
+		if len(row) > 0 {
+			WithRow(row...)(markup)
+		}
+	}
+}
+
+// WithTrailingRow adds the row after the rows of the other options, whatever
+// their order, e.g. for the Back and Cancel buttons.
+func WithTrailingRow(buttons ...InlineKeyboardButton) func(markup *InlineKeyboardMarkup) {
+	return func(markup *InlineKeyboardMarkup) {
+		markup.trailing = append(markup.trailing, WithRow(buttons...))
+	}
+}
+
+func gridRows(columns int, buttons []InlineKeyboardButton) [][]InlineKeyboardButton {
+	columns = clampColumns(columns)
+
+	var rows [][]InlineKeyboardButton
+
+	for len(buttons) > columns {
+		rows = append(rows, buttons[:columns])
+		buttons = buttons[columns:]
+	}
+
+	if len(buttons) > 0 {
+		rows = append(rows, buttons)
+	}
+
+	return rows
+}
+
+func clampColumns(columns int) int {
+	if columns < 1 {
+		return 1
+	}
+
+	if columns > maxRowButtons {
+		return maxRowButtons
+	}
+
+	return columns
+}
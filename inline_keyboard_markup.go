@@ -1,16 +1,129 @@
 package botty
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
+// LoginURL logs the user in on the website of the bot using Telegram Login.
+// Doc https://core.telegram.org/bots/api#loginurl
+type LoginURL struct {
+	URL                string `json:"url"`
+	ForwardText        string `json:"forward_text,omitempty"`
+	BotUsername        string `json:"bot_username,omitempty"`
+	RequestWriteAccess bool   `json:"request_write_access,omitempty"`
+}
+
+// SwitchInlineQueryChosenChat asks the user to pick a chat of the allowed types
+// and inserts the username of the bot and the query in the input field.
+type SwitchInlineQueryChosenChat struct {
+	Query             string `json:"query,omitempty"`
+	AllowUserChats    bool   `json:"allow_user_chats,omitempty"`
+	AllowBotChats     bool   `json:"allow_bot_chats,omitempty"`
+	AllowGroupChats   bool   `json:"allow_group_chats,omitempty"`
+	AllowChannelChats bool   `json:"allow_channel_chats,omitempty"`
+}
+
+// CallbackGame is a placeholder, it holds no information.
+type CallbackGame struct{}
+
+// InlineKeyboardButton must have exactly one of the optional fields set, the
+// Unique key counts as the callback data.
+// Doc https://core.telegram.org/bots/api#inlinekeyboardbutton
 type InlineKeyboardButton struct {
-	Text         string `json:"text"`
-	URL          string `json:"url,omitempty"`
-	CallbackData string `json:"callback_data,omitempty"`
-	Unique       string `json:"unique,omitempty"`
+	Text                         string                       `json:"text"`
+	URL                          string                       `json:"url,omitempty"`
+	CallbackData                 string                       `json:"callback_data,omitempty"`
+	Unique                       string                       `json:"unique,omitempty"`
+	WebApp                       *WebAppInfo                  `json:"web_app,omitempty"`
+	LoginURL                     *LoginURL                    `json:"login_url,omitempty"`
+	SwitchInlineQuery            *string                      `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrentChat *string                      `json:"switch_inline_query_current_chat,omitempty"`
+	SwitchInlineQueryChosenChat  *SwitchInlineQueryChosenChat `json:"switch_inline_query_chosen_chat,omitempty"`
+	CallbackGame                 *CallbackGame                `json:"callback_game,omitempty"`
+	Pay                          bool                         `json:"pay,omitempty"`
+}
+
+func NewInlineKeyboardButtonData(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+func NewInlineKeyboardButtonURL(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// NewInlineKeyboardButtonWebApp returns a button opening the Web App, in private chats only.
+func NewInlineKeyboardButtonWebApp(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+func NewInlineKeyboardButtonLoginURL(text string, loginURL LoginURL) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, LoginURL: &loginURL}
+}
+
+// NewInlineKeyboardButtonSwitchInlineQuery returns a button asking the user to
+// pick a chat and inserting the username of the bot and the query in its input field.
+func NewInlineKeyboardButtonSwitchInlineQuery(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+// NewInlineKeyboardButtonSwitchInlineQueryCurrentChat returns a button inserting
+// the username of the bot and the query in the input field of the current chat.
+func NewInlineKeyboardButtonSwitchInlineQueryCurrentChat(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query}
+}
+
+func NewInlineKeyboardButtonSwitchInlineQueryChosenChat(text string, chosenChat SwitchInlineQueryChosenChat) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryChosenChat: &chosenChat}
+}
+
+// NewInlineKeyboardButtonGame returns a button launching the game, it must be
+// the first button of the first row.
+func NewInlineKeyboardButtonGame(text string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackGame: &CallbackGame{}}
+}
+
+// NewInlineKeyboardButtonPay returns a pay button of an invoice, it must be the
+// first button of the first row.
+func NewInlineKeyboardButtonPay(text string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, Pay: true}
+}
+
+// Validate checks that the button has a text and exactly one of the optional fields set.
+func (b *InlineKeyboardButton) Validate() error {
+	if b.Text == "" {
+		return fmt.Errorf("text is required")
+	}
+
+	set := 0
+	for _, ok := range []bool{
+		b.URL != "",
+		b.CallbackData != "" || b.Unique != "",
+		b.WebApp != nil,
+		b.LoginURL != nil,
+		b.SwitchInlineQuery != nil,
+		b.SwitchInlineQueryCurrentChat != nil,
+		b.SwitchInlineQueryChosenChat != nil,
+		b.CallbackGame != nil,
+		b.Pay,
+	} {
+		if ok {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return fmt.Errorf("button %q must have exactly one of the optional fields set, got %d", b.Text, set)
+	}
+
+	return nil
 }
 
 type InlineKeyboardMarkup struct {
 	InlineKeyboardMarkup [][]InlineKeyboardButton `json:"inline_keyboard"`
+
+	// trailing adds the rows of WithTrailingRow once the other options are applied.
+	trailing []func(markup *InlineKeyboardMarkup)
 }
 
 func WithRow(buttons ...InlineKeyboardButton) func(markup *InlineKeyboardMarkup) {
@@ -36,12 +149,56 @@ func NewInlineKeyboardMarkup(opts ...func(markup *InlineKeyboardMarkup)) *Inline
 		opt(m)
 	}
 
+	for _, opt := range m.trailing {
+		opt(m)
+	}
+
+	m.trailing = nil
+
 	return m
 }
 
+// Validate checks every button, the game and pay buttons must also be the
+// first button of the first row.
+func (m *InlineKeyboardMarkup) Validate() error {
+	for i, row := range m.InlineKeyboardMarkup {
+		for j := range row {
+			b := &row[j]
+
+			if err := b.Validate(); err != nil {
+				return fmt.Errorf("invalid button %d of row %d, %w", j+1, i+1, err)
+			}
+
+			if (b.CallbackGame != nil || b.Pay) && (i != 0 || j != 0) {
+				return fmt.Errorf("invalid button %d of row %d, %q must be the first button of the first row", j+1, i+1, b.Text)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the markup, a keyboard without rows is encoded as an
+// empty array since Telegram rejects null.
+func (m *InlineKeyboardMarkup) MarshalJSON() ([]byte, error) {
+	type markup InlineKeyboardMarkup
+
+	v := *m
+	if v.InlineKeyboardMarkup == nil {
+		v.InlineKeyboardMarkup = [][]InlineKeyboardButton{}
+	}
+
+	return json.Marshal((*markup)(&v))
+}
+
+// GetText returns the JSON encoded markup, or an empty string if it can't be encoded.
+//
+// Deprecated: Use MarshalJSON, which reports the error.
 func (m *InlineKeyboardMarkup) GetText() string {
-	// TODO handle error
-	data, _ := json.Marshal(m)
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return ""
+	}
 
 	return string(data)
 }
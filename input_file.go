@@ -0,0 +1,202 @@
+package botty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InputFile is a file to send: the file_id of a file stored on the Telegram
+// servers, a URL Telegram downloads the file from, or a file uploaded by the bot.
+// The zero InputFile is no file.
+type InputFile struct {
+	ref    string
+	path   string
+	name   string
+	reader io.Reader
+}
+
+// NewInputFile detects the kind of the file: URLs with the http or https
+// scheme are downloaded by Telegram, file paths are uploaded, anything else is
+// sent as a file_id. File ids never contain a dot or a slash, so "cat.jpg" is a
+// path, and a missing file is reported when the file is sent.
+func NewInputFile(file string) InputFile {
+	if strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://") {
+		return NewInputFileURL(file)
+	}
+
+	if strings.ContainsAny(file, "./"+string(filepath.Separator)) {
+		return NewInputFilePath(file)
+	}
+
+	return NewInputFileID(file)
+}
+
+// NewInputFileID returns a file stored on the Telegram servers, e.g. the file_id of a received photo.
+func NewInputFileID(fileID string) InputFile {
+	return InputFile{ref: fileID}
+}
+
+// NewInputFileURL returns a file Telegram downloads from the URL.
+func NewInputFileURL(url string) InputFile {
+	return InputFile{ref: url}
+}
+
+// NewInputFilePath returns a local file uploaded by the bot, it is opened when the file is sent.
+func NewInputFilePath(path string) InputFile {
+	return InputFile{path: path, name: filepath.Base(path)}
+}
+
+// NewInputFileReader returns a file uploaded by the bot with the contents read
+// from r, the name is the file name Telegram shows, e.g. for documents.
+func NewInputFileReader(name string, r io.Reader) InputFile {
+	return InputFile{name: name, reader: r}
+}
+
+func (f InputFile) isZero() bool {
+	return f.ref == "" && f.path == "" && f.reader == nil
+}
+
+func (f InputFile) isUpload() bool {
+	return f.path != "" || f.reader != nil
+}
+
+func (f InputFile) addToForm(form MultipartForm, field string) error {
+	if f.reader != nil {
+		return form.AddReader(field, f.name, f.reader)
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	return form.AddFile(field, file)
+}
+
+// sendMediaRequest holds the fields shared by the requests sending media.
+type sendMediaRequest struct {
+	ChatID                   int             `json:"chat_id"`
+	MessageThreadID          int             `json:"message_thread_id,omitempty"`
+	Caption                  string          `json:"caption,omitempty"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	CaptionEntities          []MessageEntity `json:"caption_entities,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int             `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              json.RawMessage `json:"reply_markup,omitempty"`
+}
+
+// mediaOptions are the fields of the SendXData types shared by the requests sending media.
+type mediaOptions struct {
+	ChatID                   int
+	MessageThreadID          int
+	ReplyToMessageID         int
+	Caption                  string
+	ParseMode                string
+	CaptionEntities          []MessageEntity
+	DisableNotification      bool
+	ProtectContent           bool
+	AllowSendingWithoutReply bool
+	ReplyMarkup              ReplyMarkup
+}
+
+func (c *Client) newSendMediaRequest(ctx context.Context, o mediaOptions) (sendMediaRequest, error) {
+	markup, err := c.encodeReplyMarkup(ctx, o.ReplyMarkup)
+	if err != nil {
+		return sendMediaRequest{}, err
+	}
+
+	return sendMediaRequest{
+		ChatID:                   o.ChatID,
+		MessageThreadID:          o.MessageThreadID,
+		Caption:                  o.Caption,
+		ParseMode:                o.ParseMode,
+		CaptionEntities:          prepareEntities(o.Caption, o.CaptionEntities),
+		DisableNotification:      o.DisableNotification,
+		ProtectContent:           o.ProtectContent,
+		ReplyToMessageID:         o.ReplyToMessageID,
+		AllowSendingWithoutReply: o.AllowSendingWithoutReply,
+		ReplyMarkup:              markup,
+	}, nil
+}
+
+// validateThumbnail checks that the thumbnail is uploaded, Telegram can't reuse
+// a thumbnail by its file_id or download it from a URL.
+func validateThumbnail(thumbnail InputFile) error {
+	if !thumbnail.isZero() && !thumbnail.isUpload() {
+		return fmt.Errorf("thumbnail must be uploaded, not a file_id or URL")
+	}
+
+	return nil
+}
+
+// sendMedia sends the request along with the files. The files stored by
+// Telegram are sent as the fields of the request, if any file has to be
+// uploaded, the request is sent as a multipart form.
+func (c *Client) sendMedia(ctx context.Context, method string, chatID int, req interface{}, files map[string]InputFile) (*Message, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode request, %w", err)
+	}
+
+	var params map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("can't encode request, %w", err)
+	}
+
+	upload := false
+
+	for field, f := range files {
+		switch {
+		case f.isUpload():
+			upload = true
+		case !f.isZero():
+			ref, err := json.Marshal(f.ref)
+			if err != nil {
+				return nil, fmt.Errorf("can't encode %s, %w", field, err)
+			}
+
+			params[field] = ref
+		}
+	}
+
+	if !upload {
+		res, err := c.doRequest(ctx, method, chatID, params)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.processResponse(res)
+	}
+
+	form := NewMultipartForm()
+
+	if err := addFieldsToForm(form, params); err != nil {
+		return nil, err
+	}
+
+	for field, f := range files {
+		if !f.isUpload() {
+			continue
+		}
+
+		if err := f.addToForm(form, field); err != nil {
+			return nil, fmt.Errorf("can't add %s to form, %w", field, err)
+		}
+	}
+
+	res, err := c.doMultipartFormRequest(ctx, method, chatID, form)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.processResponse(res)
+}
@@ -0,0 +1,68 @@
+package botty
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OnMessageIgnoreCase handles messages equal to msg under Unicode case-folding.
+func (c *Client) OnMessageIgnoreCase(msg string, f HandlerFunc, middleware ...Middleware) {
+	c.addMessageRoute(priorityIgnoreCase, func(_ *Context, text string) bool {
+		return strings.EqualFold(text, msg)
+	}, f, middleware)
+}
+
+// OnMessagePrefix handles messages starting with prefix.
+func (c *Client) OnMessagePrefix(prefix string, f HandlerFunc, middleware ...Middleware) {
+	c.addMessageRoute(priorityPrefix, func(_ *Context, text string) bool {
+		return strings.HasPrefix(text, prefix)
+	}, f, middleware)
+}
+
+// OnMessageContains handles messages containing substr.
+func (c *Client) OnMessageContains(substr string, f HandlerFunc, middleware ...Middleware) {
+	c.addMessageRoute(priorityContains, func(_ *Context, text string) bool {
+		return strings.Contains(text, substr)
+	}, f, middleware)
+}
+
+// OnMessageRegex handles messages matching re, the handler gets the submatches
+// using Context.Matches and Context.NamedMatch. Use the (?i) flag for a case-insensitive match.
+func (c *Client) OnMessageRegex(re *regexp.Regexp, f HandlerFunc, middleware ...Middleware) {
+	c.addMessageRoute(priorityRegex, func(ctx *Context, text string) bool {
+		matches := re.FindStringSubmatch(text)
+		if matches == nil {
+			return false
+		}
+
+		ctx.matches = matches
+		ctx.matchNames = re.SubexpNames()
+
+		return true
+	}, f, middleware)
+}
+
+// OnMessageFunc handles text messages for which match returns true.
+func (c *Client) OnMessageFunc(match func(ctx *Context) bool, f HandlerFunc, middleware ...Middleware) {
+	c.addMessageRoute(priorityFunc, func(ctx *Context, _ string) bool {
+		return match(ctx)
+	}, f, middleware)
+}
+
+// Matches returns the submatches of the OnMessageRegex expression, the first
+// one is the whole match.
+func (ctx *Context) Matches() []string {
+	return ctx.matches
+}
+
+// NamedMatch returns the submatch of the named group of the OnMessageRegex
+// expression, or an empty string if there is no such group.
+func (ctx *Context) NamedMatch(name string) string {
+	for i, n := range ctx.matchNames {
+		if n == name && i < len(ctx.matches) {
+			return ctx.matches[i]
+		}
+	}
+
+	return ""
+}
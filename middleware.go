@@ -0,0 +1,111 @@
+package botty
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// HandlerFunc handles an update, ctx wraps the update and carries values set by middleware.
+type HandlerFunc func(ctx *Context) error
+
+// Middleware wraps a handler with cross-cutting logic, it calls next to continue handling the update.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Use adds middleware wrapping every handler registered on the client. Global
+// middleware runs before the middleware passed to a single route, in the order it was added.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+func (c *Client) handle(h HandlerFunc, ctx *Context) error {
+	return chain(h, c.middleware)(ctx)
+}
+
+func chain(h HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+
+	return h
+}
+
+// Recover turns a panic in the handler into an error.
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("handler panicked while processing update %d, %v", ctx.update.UpdateID, r)
+				}
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// Logger logs every handled update with the processing time and the handler
+// error. The standard logger is used if l is nil.
+func Logger(l *log.Logger) Middleware {
+	if l == nil {
+		l = log.Default()
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			start := time.Now()
+			err := next(ctx)
+
+			if err != nil {
+				l.Printf("update %d handled in %s, error: %v", ctx.update.UpdateID, time.Since(start), err)
+			} else {
+				l.Printf("update %d handled in %s", ctx.update.UpdateID, time.Since(start))
+			}
+
+			return err
+		}
+	}
+}
+
+// AllowChats skips updates that don't belong to one of the chats.
+func AllowChats(ids ...int) Middleware {
+	allowed := toSet(ids)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			chat := ctx.Chat()
+			if chat == nil || !allowed[chat.ID] {
+				return nil
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// AllowUsers skips updates that are not sent by one of the users.
+func AllowUsers(ids ...int) Middleware {
+	allowed := toSet(ids)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			sender := ctx.Sender()
+			if sender == nil || !allowed[sender.ID] {
+				return nil
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func toSet(ids []int) map[int]bool {
+	set := make(map[int]bool, len(ids))
+
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	return set
+}
@@ -13,6 +13,7 @@ type MultipartForm interface {
 	Form() *bytes.Buffer
 	AddField(name, value string) error
 	AddFile(name string, f *os.File) error
+	AddReader(name, fileName string, r io.Reader) error
 }
 
 type multipartForm struct {
@@ -34,11 +35,15 @@ func (m *multipartForm) AddField(name, value string) error {
 }
 
 func (m *multipartForm) AddFile(name string, f *os.File) error {
-	part, err := m.writer.CreateFormFile(name, filepath.Base(f.Name()))
+	return m.AddReader(name, filepath.Base(f.Name()), f)
+}
+
+func (m *multipartForm) AddReader(name, fileName string, r io.Reader) error {
+	part, err := m.writer.CreateFormFile(name, fileName)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(part, f)
+	_, err = io.Copy(part, r)
 
 	return err
 }
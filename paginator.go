@@ -0,0 +1,196 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	defaultPrevPageText = "◀"
+	defaultNextPageText = "▶"
+
+	// currentPageData is the data of the button with the page number, pressing it does nothing.
+	currentPageData = "-"
+)
+
+// PageSource returns the items of the page starting at offset and the total number of items.
+type PageSource[T any] func(ctx context.Context, offset, limit int) (items []T, total int, err error)
+
+type paginatorOptions struct {
+	prevText string
+	nextText string
+	columns  int
+	text     func(page, pages int) string
+	trailing []InlineKeyboardButton
+}
+
+type PaginatorOption func(o *paginatorOptions)
+
+// WithPageButtons sets the labels of the buttons opening the previous and the next page.
+func WithPageButtons(prev, next string) PaginatorOption {
+	return func(o *paginatorOptions) {
+		o.prevText = prev
+		o.nextText = next
+	}
+}
+
+// WithPageColumns lays the items of the page out in a balanced grid of columns.
+func WithPageColumns(columns int) PaginatorOption {
+	return func(o *paginatorOptions) {
+		o.columns = columns
+	}
+}
+
+// WithPageTrailingRow adds the row after the navigation row, e.g. for the Back button.
+func WithPageTrailingRow(buttons ...InlineKeyboardButton) PaginatorOption {
+	return func(o *paginatorOptions) {
+		o.trailing = buttons
+	}
+}
+
+// WithPageText sets the text of the message with the page, page starts at 1.
+func WithPageText(text func(page, pages int) string) PaginatorOption {
+	return func(o *paginatorOptions) {
+		o.text = text
+	}
+}
+
+// Paginator shows the items of a source as pages of inline buttons with a
+// "◀ 1/5 ▶" navigation row, paging edits the message in place.
+type Paginator[T any] struct {
+	unique   string
+	pageSize int
+	source   PageSource[T]
+	render   func(item T) InlineKeyboardButton
+	options  paginatorOptions
+}
+
+// NewPaginator returns a paginator showing pageSize items per page, render
+// returns the button of an item, e.g. with the callback data handled by another
+// route. The paginator handles the navigation buttons with the unique key using OnQuery.
+func NewPaginator[T any](c *Client, unique string, pageSize int, source PageSource[T], render func(item T) InlineKeyboardButton, options ...PaginatorOption) *Paginator[T] {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
+	p := &Paginator[T]{
+		unique:   unique,
+		pageSize: pageSize,
+		source:   source,
+		render:   render,
+		options: paginatorOptions{
+			prevText: defaultPrevPageText,
+			nextText: defaultNextPageText,
+			columns:  1,
+			text: func(page, pages int) string {
+				return fmt.Sprintf("Page %d of %d", page, pages)
+			},
+		},
+	}
+
+	for _, o := range options {
+		o(&p.options)
+	}
+
+	c.OnQuery(unique, p.handle)
+
+	return p
+}
+
+// Reply sends the first page to the chat of the update.
+func (p *Paginator[T]) Reply(ctx *Context, options ...ReplyOption) error {
+	text, markup, err := p.Page(ctx.Context(), 0)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Reply(text, append(options, WithReplyMarkup(markup))...)
+}
+
+// Page returns the text and the keyboard of the page, page starts at 0. Pages
+// past the last one are replaced by the last one.
+func (p *Paginator[T]) Page(ctx context.Context, page int) (string, *InlineKeyboardMarkup, error) {
+	if page < 0 {
+		page = 0
+	}
+
+	items, total, err := p.source(ctx, page*p.pageSize, p.pageSize)
+	if err != nil {
+		return "", nil, fmt.Errorf("can't load page %d, %w", page, err)
+	}
+
+	pages := (total + p.pageSize - 1) / p.pageSize
+	if pages == 0 {
+		pages = 1
+	}
+
+	// the items could have been removed since the page was shown
+	if page >= pages {
+		return p.Page(ctx, pages-1)
+	}
+
+	buttons := make([]InlineKeyboardButton, len(items))
+	for i, item := range items {
+		buttons[i] = p.render(item)
+	}
+
+	opts := []func(markup *InlineKeyboardMarkup){
+		WithBalancedGrid(p.options.columns, buttons...),
+	}
+
+	if pages > 1 {
+		opts = append(opts, WithRow(p.navigation(page, pages)...))
+	}
+
+	if len(p.options.trailing) > 0 {
+		opts = append(opts, WithTrailingRow(p.options.trailing...))
+	}
+
+	markup := NewInlineKeyboardMarkup(opts...)
+
+	return p.options.text(page+1, pages), markup, nil
+}
+
+func (p *Paginator[T]) navigation(page, pages int) []InlineKeyboardButton {
+	var row []InlineKeyboardButton
+
+	if page > 0 {
+		row = append(row, p.button(p.options.prevText, strconv.Itoa(page-1)))
+	}
+
+	row = append(row, p.button(fmt.Sprintf("%d/%d", page+1, pages), currentPageData))
+
+	if page < pages-1 {
+		row = append(row, p.button(p.options.nextText, strconv.Itoa(page+1)))
+	}
+
+	return row
+}
+
+func (p *Paginator[T]) button(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{
+		Text:         text,
+		CallbackData: data,
+		Unique:       p.unique,
+	}
+}
+
+func (p *Paginator[T]) handle(ctx *Context) error {
+	data := ctx.update.CallbackQuery.Data
+	if data == currentPageData {
+		return nil
+	}
+
+	page, err := strconv.Atoi(data)
+	if err != nil {
+		return nil
+	}
+
+	text, markup, err := p.Page(ctx.Context(), page)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Edit(text, WithReplyMarkup(markup))
+}
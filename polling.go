@@ -0,0 +1,231 @@
+package botty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollLimit      = 100
+	maxPollLimit          = 100
+	defaultPollTimeout    = 30 * time.Second
+	defaultMinPollBackoff = time.Second
+	defaultMaxPollBackoff = time.Minute
+
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+// Update types accepted by WithAllowedUpdates.
+const (
+	UpdateTypeMessage            = "message"
+	UpdateTypeEditedMessage      = "edited_message"
+	UpdateTypeChannelPost        = "channel_post"
+	UpdateTypeEditedChannelPost  = "edited_channel_post"
+	UpdateTypeInlineQuery        = "inline_query"
+	UpdateTypeChosenInlineResult = "chosen_inline_result"
+	UpdateTypeCallbackQuery      = "callback_query"
+	UpdateTypeShippingQuery      = "shipping_query"
+	UpdateTypePreCheckoutQuery   = "pre_checkout_query"
+	UpdateTypePoll               = "poll"
+	UpdateTypePollAnswer         = "poll_answer"
+	UpdateTypeMyChatMember       = "my_chat_member"
+	UpdateTypeChatMember         = "chat_member"
+	UpdateTypeChatJoinRequest    = "chat_join_request"
+)
+
+type getUpdatesRequest struct {
+	Offset         int       `json:"offset"`
+	Limit          int       `json:"limit"`
+	Timeout        int       `json:"timeout"`
+	AllowedUpdates *[]string `json:"allowed_updates,omitempty"`
+}
+
+// allowedUpdatesToRequest keeps an empty list in the request, since it resets
+// the allowed updates, unlike a missing one.
+func allowedUpdatesToRequest(allowedUpdates []string) *[]string {
+	if allowedUpdates == nil {
+		return nil
+	}
+
+	return &allowedUpdates
+}
+
+// WithPollLimit sets the maximum number of updates received by a single poll,
+// from 1 to 100. Values outside the range are clamped to it.
+func WithPollLimit(limit int) ClientOption {
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > maxPollLimit {
+		limit = maxPollLimit
+	}
+
+	return func(c *Client) {
+		c.pollLimit = limit
+	}
+}
+
+// WithPollTimeout sets the long polling timeout. A zero timeout switches to short polling.
+func WithPollTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.pollTimeout = timeout
+	}
+}
+
+// WithAllowedUpdates limits the update types the bot receives. Telegram keeps the
+// list between requests, so passing no types resets it to the default set.
+func WithAllowedUpdates(types ...string) ClientOption {
+	return func(c *Client) {
+		c.allowedUpdates = make([]string, len(types))
+		copy(c.allowedUpdates, types)
+	}
+}
+
+// WithPollBackoff sets the delay bounds between failed polls. The delay doubles
+// after every failure, starting at min and never exceeding max. A zero or
+// negative min or max is replaced by the default of 1s or 1m respectively.
+func WithPollBackoff(min, max time.Duration) ClientOption {
+	if min <= 0 {
+		min = defaultMinPollBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxPollBackoff
+	}
+	if max < min {
+		max = min
+	}
+
+	return func(c *Client) {
+		c.minPollBackoff = min
+		c.maxPollBackoff = max
+	}
+}
+
+// WithShutdownTimeout sets how long RunContext and RunWebhookContext wait for
+// in-flight handlers once their context is done.
+func WithShutdownTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.shutdownTimeout = timeout
+	}
+}
+
+// poll receives updates and passes them to the dispatcher until ctx is done.
+// It only returns an error when the bot token is rejected, retrying won't help then.
+func (c *Client) poll(ctx context.Context, d *dispatcher) error {
+	var backoff time.Duration
+
+	for {
+		updates, err := c.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if IsUnauthorized(err) {
+				return err
+			}
+
+			c.handleError(err)
+
+			backoff = c.nextPollBackoff(backoff)
+
+			if retryAfter := time.Duration(RetryAfter(err)) * time.Second; retryAfter > backoff {
+				backoff = retryAfter
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+
+			continue
+		}
+
+		backoff = 0
+
+		for _, u := range updates {
+			c.offsets.received(u.UpdateID)
+
+			if !d.dispatch(ctx, u) {
+				return nil
+			}
+		}
+	}
+}
+
+// commitOffset confirms the processed updates, Telegram considers an update
+// confirmed as soon as getUpdates is called with a greater offset.
+func (c *Client) commitOffset(ctx context.Context) error {
+	offset := c.offsets.commitOffset()
+	if offset == 0 {
+		return nil
+	}
+
+	res, err := c.updates(ctx, offset, 1, 0)
+	if err != nil {
+		return fmt.Errorf("can't commit offset, %w", err)
+	}
+	if !res.OK {
+		return fmt.Errorf("can't commit offset, %w", newAPIError(res.ErrorCode, res.Description, res.Parameters))
+	}
+
+	return nil
+}
+
+func (c *Client) nextPollBackoff(prev time.Duration) time.Duration {
+	next := prev * 2
+	if next < c.minPollBackoff {
+		next = c.minPollBackoff
+	}
+	if next > c.maxPollBackoff {
+		next = c.maxPollBackoff
+	}
+
+	return next
+}
+
+func (c *Client) handleError(err error) {
+	if c.errorHandler != nil {
+		c.errorHandler(err)
+	}
+}
+
+func (c *Client) getUpdates(ctx context.Context) ([]Update, error) {
+	updates, err := c.updates(ctx, c.offsets.fetchOffset(), c.pollLimit, c.pollTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while receiving updates, %w", err)
+	}
+	if !updates.OK {
+		return nil, fmt.Errorf("can't get updates, %w", newAPIError(updates.ErrorCode, updates.Description, updates.Parameters))
+	}
+
+	return updates.Result, nil
+}
+
+func (c *Client) updates(ctx context.Context, offset, limit int, timeout time.Duration) (UpdateResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout+requestTimeoutMargin)
+	defer cancel()
+
+	req := &getUpdatesRequest{
+		Offset:         offset,
+		Limit:          limit,
+		Timeout:        int(timeout / time.Second),
+		AllowedUpdates: allowedUpdatesToRequest(c.allowedUpdates),
+	}
+
+	data, err := c.doRequest(ctx, methodGetUpdates, 0, req)
+	if err != nil {
+		return UpdateResponse{}, fmt.Errorf("can't get updates, %w", err)
+	}
+
+	var res UpdateResponse
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return UpdateResponse{}, fmt.Errorf("can't get updates, %w", err)
+	}
+
+	return res, nil
+}
@@ -1,45 +1,161 @@
 package botty
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
+// answerTextLimit is the maximum length of the callback query answer text in characters.
+const answerTextLimit = 200
+
 type AnswerCallbackQueryData struct {
 	CallbackQueryID string
 	Text            string
+	ShowAlert       bool
+	URL             string
+	CacheTime       int // seconds the client may cache the answer for
+}
+
+type answerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+	URL             string `json:"url,omitempty"`
+	CacheTime       int    `json:"cache_time,omitempty"`
 }
 
 type ReplyToQueryResponse struct {
-	OK          bool   `json:"ok"`
-	Result      bool   `json:"result"`
-	Description string `json:"description"`
+	OK          bool                `json:"ok"`
+	Result      bool                `json:"result"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+}
+
+// AnswerOption configures the answer sent by Context.Answer.
+type AnswerOption func(d *AnswerCallbackQueryData)
+
+// WithShowAlert shows the answer as an alert instead of a notification at the top of the chat.
+func WithShowAlert() AnswerOption {
+	return func(d *AnswerCallbackQueryData) {
+		d.ShowAlert = true
+	}
+}
+
+// WithAnswerURL makes the client open the URL, e.g. a t.me link that starts the
+// bot with a parameter, or the URL of the game of a callback game button.
+func WithAnswerURL(url string) AnswerOption {
+	return func(d *AnswerCallbackQueryData) {
+		d.URL = url
+	}
+}
+
+// WithCacheTime lets the client cache the answer, so pressing the button again doesn't reach the bot.
+func WithCacheTime(d time.Duration) AnswerOption {
+	return func(data *AnswerCallbackQueryData) {
+		data.CacheTime = int(d / time.Second)
+	}
 }
 
-func (c *Client) replyToQuery(u Update) error {
-	v := url.Values{}
+func (d *AnswerCallbackQueryData) validate() error {
+	if d.CallbackQueryID == "" {
+		return fmt.Errorf("callback_query_id is required")
+	}
+
+	if utf8.RuneCountInString(d.Text) > answerTextLimit {
+		return fmt.Errorf("text exceeds %d characters", answerTextLimit)
+	}
+
+	return nil
+}
+
+func (c *Client) replyToQuery(ctx context.Context, u Update) error {
 	m := &AnswerCallbackQueryData{
 		CallbackQueryID: u.CallbackQuery.ID,
 	}
 
-	v.Add("callback_query_id", m.CallbackQueryID)
+	return c.AnswerCallbackQueryContext(ctx, m)
+}
+
+// AnswerCallbackQuery answers the callback query, the answer is shown as a
+// notification or an alert. Queries the handlers didn't answer, using this
+// method or Context.Answer, are answered with an empty answer after the handlers.
+func (c *Client) AnswerCallbackQuery(d *AnswerCallbackQueryData) error {
+	return c.AnswerCallbackQueryContext(context.Background(), d)
+}
+
+func (c *Client) AnswerCallbackQueryContext(ctx context.Context, d *AnswerCallbackQueryData) (err error) {
+	defer func() { err = wrapIfErr("can't answer callback query", err) }()
+
+	if err := d.validate(); err != nil {
+		return err
+	}
+
+	req := &answerCallbackQueryRequest{
+		CallbackQueryID: d.CallbackQueryID,
+		Text:            d.Text,
+		ShowAlert:       d.ShowAlert,
+		URL:             d.URL,
+		CacheTime:       d.CacheTime,
+	}
 
-	res, err := c.doRequest(methodAnswerCallbackQuery, v)
+	res, err := c.doRequest(ctx, methodAnswerCallbackQuery, 0, req)
 	if err != nil {
-		return fmt.Errorf("can't send query callback response, %w", err)
+		return err
 	}
 
 	decodedRes := new(ReplyToQueryResponse)
 	if err := json.Unmarshal(res, decodedRes); err != nil {
-		return fmt.Errorf("can't decode response after replying to query")
+		return fmt.Errorf("can't decode response, %w", err)
 	}
 	if !decodedRes.OK {
-		if decodedRes.Description != "" {
-			return fmt.Errorf("can't decode response after replying to query, %s", decodedRes.Description)
-		}
-		return fmt.Errorf("can't decode response after replying to query")
+		return newAPIError(decodedRes.ErrorCode, decodedRes.Description, decodedRes.Parameters)
 	}
 
+	c.queries.answer(d.CallbackQueryID)
+
 	return nil
 }
+
+// queryTracker records which of the callback queries being processed were
+// answered, so they don't get the empty answer after the handlers.
+type queryTracker struct {
+	mu       sync.Mutex
+	answered map[string]bool
+}
+
+func (t *queryTracker) start(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.answered == nil {
+		t.answered = make(map[string]bool)
+	}
+
+	t.answered[id] = false
+}
+
+// answer marks the query as answered, queries that are not being processed are ignored.
+func (t *queryTracker) answer(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.answered[id]; ok {
+		t.answered[id] = true
+	}
+}
+
+// finish forgets the query and reports whether it was answered.
+func (t *queryTracker) finish(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	answered := t.answered[id]
+	delete(t.answered, id)
+
+	return answered
+}
@@ -0,0 +1,189 @@
+package botty
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxIdleBuckets is the number of per-chat buckets after which the buckets of
+// idle chats are dropped.
+const maxIdleBuckets = 1024
+
+// RateLimiter delays outgoing requests to a chat until they can be sent without
+// exceeding the flood limits.
+type RateLimiter interface {
+	Wait(ctx context.Context, chatID int) error
+}
+
+// Rate allows Limit requests per the Per period, all of them can be sent at once.
+type Rate struct {
+	Limit int
+	Per   time.Duration
+}
+
+// RateLimits are the rates applied by the limiter returned by NewRateLimiter.
+// Chats with a negative ID, i.e. groups, supergroups and channels, are limited
+// by GroupChat, the others are limited by PrivateChat. Every request is also
+// limited by Global. A zero Rate disables the limit.
+type RateLimits struct {
+	Global      Rate
+	PrivateChat Rate
+	GroupChat   Rate
+}
+
+// DefaultRateLimits are the limits documented in the Telegram Bot FAQ.
+var DefaultRateLimits = RateLimits{
+	Global:      Rate{Limit: 30, Per: time.Second},
+	PrivateChat: Rate{Limit: 1, Per: time.Second},
+	GroupChat:   Rate{Limit: 20, Per: time.Minute},
+}
+
+// WithRateLimiter makes the client wait for the limiter before every request
+// sent to a chat, e.g. WithRateLimiter(NewRateLimiter(DefaultRateLimits)).
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+type rateLimiter struct {
+	limits RateLimits
+	mu     sync.Mutex
+	global *tokenBucket
+	chats  map[int]*tokenBucket
+}
+
+// NewRateLimiter returns a limiter that queues requests using a global token
+// bucket and a token bucket per chat.
+func NewRateLimiter(limits RateLimits) RateLimiter {
+	return &rateLimiter{
+		limits: limits,
+		global: newTokenBucket(limits.Global),
+		chats:  make(map[int]*tokenBucket),
+	}
+}
+
+func (l *rateLimiter) Wait(ctx context.Context, chatID int) error {
+	if err := l.wait(ctx, l.chatBucket(chatID)); err != nil {
+		return err
+	}
+
+	return l.wait(ctx, l.global)
+}
+
+func (l *rateLimiter) wait(ctx context.Context, b *tokenBucket) error {
+	if b == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	delay := b.reserve(time.Now())
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		b.cancel()
+		l.mu.Unlock()
+
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (l *rateLimiter) chatBucket(chatID int) *tokenBucket {
+	rate := l.limits.PrivateChat
+	if chatID < 0 {
+		rate = l.limits.GroupChat
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.chats[chatID]; ok {
+		return b
+	}
+
+	b := newTokenBucket(rate)
+	if b == nil {
+		return nil
+	}
+
+	if len(l.chats) >= maxIdleBuckets {
+		l.dropIdleBuckets(time.Now())
+	}
+
+	l.chats[chatID] = b
+
+	return b
+}
+
+func (l *rateLimiter) dropIdleBuckets(now time.Time) {
+	for chatID, b := range l.chats {
+		if b.idle(now) {
+			delete(l.chats, chatID)
+		}
+	}
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate Rate) *tokenBucket {
+	if rate.Limit <= 0 || rate.Per <= 0 {
+		return nil
+	}
+
+	return &tokenBucket{
+		rate:   float64(rate.Limit) / rate.Per.Seconds(),
+		burst:  float64(rate.Limit),
+		tokens: float64(rate.Limit),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+
+	b.last = now
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+// Tokens go negative while requests are queued.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) cancel() {
+	b.tokens++
+}
+
+func (b *tokenBucket) idle(now time.Time) bool {
+	b.refill(now)
+
+	return b.tokens >= b.burst
+}
@@ -1,5 +1,10 @@
 package botty
 
+import (
+	"context"
+	"fmt"
+)
+
 type ReplyOption func(s *MessageData)
 
 func WithParseMode(mode string) ReplyOption {
@@ -57,8 +62,18 @@ func WithReplyMarkup(markup ReplyMarkup) ReplyOption {
 }
 
 func (c *Client) Reply(u Update, text string, options ...ReplyOption) error {
+	return c.ReplyContext(context.Background(), u, text, options...)
+}
+
+// ReplyContext sends a message to the chat of the update, e.g. the chat of the message with the pressed button.
+func (c *Client) ReplyContext(ctx context.Context, u Update, text string, options ...ReplyOption) error {
+	chat := u.chat()
+	if chat == nil {
+		return fmt.Errorf("can't reply, the update has no chat")
+	}
+
 	m := &MessageData{
-		ChatID: u.Message.Chat.ID,
+		ChatID: chat.ID,
 		Text:   text,
 	}
 
@@ -66,6 +81,6 @@ func (c *Client) Reply(u Update, text string, options ...ReplyOption) error {
 		o(m)
 	}
 
-	_, err := c.SendMessage(m)
+	_, err := c.SendMessageContext(ctx, m)
 	return err
 }
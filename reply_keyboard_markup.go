@@ -0,0 +1,292 @@
+package botty
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// placeholderLimit is the maximum length of the input field placeholder in characters.
+const placeholderLimit = 64
+
+// Poll types accepted by NewKeyboardButtonPoll.
+const (
+	PollTypeAny     = ""
+	PollTypeQuiz    = "quiz"
+	PollTypeRegular = "regular"
+)
+
+// WebAppInfo describes a Web App.
+// Doc https://core.telegram.org/bots/webapps
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+// ChatAdministratorRights are the rights of an administrator in the chat.
+// Doc https://core.telegram.org/bots/api#chatadministratorrights
+type ChatAdministratorRights struct {
+	IsAnonymous         bool `json:"is_anonymous"`
+	CanManageChat       bool `json:"can_manage_chat"`
+	CanDeleteMessages   bool `json:"can_delete_messages"`
+	CanManageVideoChats bool `json:"can_manage_video_chats"`
+	CanRestrictMembers  bool `json:"can_restrict_members"`
+	CanPromoteMembers   bool `json:"can_promote_members"`
+	CanChangeInfo       bool `json:"can_change_info"`
+	CanInviteUsers      bool `json:"can_invite_users"`
+	CanPostMessages     bool `json:"can_post_messages,omitempty"`
+	CanEditMessages     bool `json:"can_edit_messages,omitempty"`
+	CanPinMessages      bool `json:"can_pin_messages,omitempty"`
+	CanManageTopics     bool `json:"can_manage_topics,omitempty"`
+}
+
+// KeyboardButtonRequestUser asks the user to pick a user, the bot receives the
+// identifier of the picked user in a service message. A nil criterion isn't applied.
+type KeyboardButtonRequestUser struct {
+	RequestID     int   `json:"request_id"`
+	UserIsBot     *bool `json:"user_is_bot,omitempty"`
+	UserIsPremium *bool `json:"user_is_premium,omitempty"`
+}
+
+// KeyboardButtonRequestChat asks the user to pick a chat, the bot receives the
+// identifier of the picked chat in a service message. A nil criterion isn't applied.
+type KeyboardButtonRequestChat struct {
+	RequestID               int                      `json:"request_id"`
+	ChatIsChannel           bool                     `json:"chat_is_channel"`
+	ChatIsForum             *bool                    `json:"chat_is_forum,omitempty"`
+	ChatHasUsername         *bool                    `json:"chat_has_username,omitempty"`
+	ChatIsCreated           *bool                    `json:"chat_is_created,omitempty"`
+	UserAdministratorRights *ChatAdministratorRights `json:"user_administrator_rights,omitempty"`
+	BotAdministratorRights  *ChatAdministratorRights `json:"bot_administrator_rights,omitempty"`
+	BotIsMember             bool                     `json:"bot_is_member,omitempty"`
+}
+
+type KeyboardButtonPollType struct {
+	Type string `json:"type,omitempty"`
+}
+
+// KeyboardButton is a button of the reply keyboard. Without the optional fields,
+// pressing it sends its text as a message.
+type KeyboardButton struct {
+	Text            string                     `json:"text"`
+	RequestUser     *KeyboardButtonRequestUser `json:"request_user,omitempty"`
+	RequestChat     *KeyboardButtonRequestChat `json:"request_chat,omitempty"`
+	RequestContact  bool                       `json:"request_contact,omitempty"`
+	RequestLocation bool                       `json:"request_location,omitempty"`
+	RequestPoll     *KeyboardButtonPollType    `json:"request_poll,omitempty"`
+	WebApp          *WebAppInfo                `json:"web_app,omitempty"`
+}
+
+func NewKeyboardButton(text string) KeyboardButton {
+	return KeyboardButton{Text: text}
+}
+
+// NewKeyboardButtonContact returns a button sending the phone number of the user, in private chats only.
+func NewKeyboardButtonContact(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestContact: true}
+}
+
+// NewKeyboardButtonLocation returns a button sending the location of the user, in private chats only.
+func NewKeyboardButtonLocation(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestLocation: true}
+}
+
+// NewKeyboardButtonPoll returns a button asking the user to create a poll of
+// the type, PollTypeAny allows any type. In private chats only.
+func NewKeyboardButtonPoll(text, pollType string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestPoll: &KeyboardButtonPollType{Type: pollType}}
+}
+
+// NewKeyboardButtonUser returns a button asking the user to pick a user, the
+// requestID identifies the request in the service message. In private chats only.
+func NewKeyboardButtonUser(text string, requestID int) KeyboardButton {
+	return KeyboardButton{Text: text, RequestUser: &KeyboardButtonRequestUser{RequestID: requestID}}
+}
+
+// NewKeyboardButtonChat returns a button asking the user to pick a channel, or
+// a group if isChannel is false. In private chats only.
+func NewKeyboardButtonChat(text string, requestID int, isChannel bool) KeyboardButton {
+	return KeyboardButton{Text: text, RequestChat: &KeyboardButtonRequestChat{RequestID: requestID, ChatIsChannel: isChannel}}
+}
+
+// NewKeyboardButtonWebApp returns a button opening the Web App, in private chats only.
+func NewKeyboardButtonWebApp(text, url string) KeyboardButton {
+	return KeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// ReplyKeyboardMarkup replaces the keyboard of the user with the buttons.
+// Doc https://core.telegram.org/bots/api#replykeyboardmarkup
+type ReplyKeyboardMarkup struct {
+	Keyboard              [][]KeyboardButton `json:"keyboard"`
+	IsPersistent          bool               `json:"is_persistent,omitempty"`
+	ResizeKeyboard        bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard       bool               `json:"one_time_keyboard,omitempty"`
+	InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"`
+	Selective             bool               `json:"selective,omitempty"`
+}
+
+func WithKeyboardRow(buttons ...KeyboardButton) func(markup *ReplyKeyboardMarkup) {
+	return func(markup *ReplyKeyboardMarkup) {
+		row := make([]KeyboardButton, len(buttons))
+		copy(row, buttons)
+
+		markup.Keyboard = append(markup.Keyboard, row)
+	}
+}
+
+// WithResizeKeyboard fits the keyboard height to the buttons.
+func WithResizeKeyboard() func(markup *ReplyKeyboardMarkup) {
+	return func(markup *ReplyKeyboardMarkup) {
+		markup.ResizeKeyboard = true
+	}
+}
+
+// WithOneTimeKeyboard hides the keyboard once a button is pressed.
+func WithOneTimeKeyboard() func(markup *ReplyKeyboardMarkup) {
+	return func(markup *ReplyKeyboardMarkup) {
+		markup.OneTimeKeyboard = true
+	}
+}
+
+// WithPersistentKeyboard always shows the keyboard when the regular keyboard is hidden.
+func WithPersistentKeyboard() func(markup *ReplyKeyboardMarkup) {
+	return func(markup *ReplyKeyboardMarkup) {
+		markup.IsPersistent = true
+	}
+}
+
+// WithKeyboardPlaceholder sets the placeholder of the input field while the keyboard is active.
+func WithKeyboardPlaceholder(placeholder string) func(markup *ReplyKeyboardMarkup) {
+	return func(markup *ReplyKeyboardMarkup) {
+		markup.InputFieldPlaceholder = placeholder
+	}
+}
+
+// WithSelectiveKeyboard shows the keyboard only to the users mentioned in the
+// text of the message and the sender of the message the bot replies to.
+func WithSelectiveKeyboard() func(markup *ReplyKeyboardMarkup) {
+	return func(markup *ReplyKeyboardMarkup) {
+		markup.Selective = true
+	}
+}
+
+func NewReplyKeyboardMarkup(opts ...func(markup *ReplyKeyboardMarkup)) *ReplyKeyboardMarkup {
+	m := new(ReplyKeyboardMarkup)
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func (m *ReplyKeyboardMarkup) MarshalJSON() ([]byte, error) {
+	type markup ReplyKeyboardMarkup
+
+	return json.Marshal((*markup)(m))
+}
+
+// Validate checks that the keyboard has buttons, every button has a text and
+// at most one of the optional fields set.
+func (m *ReplyKeyboardMarkup) Validate() error {
+	if len(m.Keyboard) == 0 {
+		return fmt.Errorf("keyboard has no buttons")
+	}
+
+	for i, row := range m.Keyboard {
+		if len(row) == 0 {
+			return fmt.Errorf("row %d has no buttons", i+1)
+		}
+
+		for j := range row {
+			if err := row[j].Validate(); err != nil {
+				return fmt.Errorf("invalid button %d of row %d, %w", j+1, i+1, err)
+			}
+		}
+	}
+
+	return validatePlaceholder(m.InputFieldPlaceholder)
+}
+
+func (b *KeyboardButton) Validate() error {
+	if b.Text == "" {
+		return fmt.Errorf("text is required")
+	}
+
+	set := 0
+	for _, ok := range []bool{
+		b.RequestUser != nil,
+		b.RequestChat != nil,
+		b.RequestContact,
+		b.RequestLocation,
+		b.RequestPoll != nil,
+		b.WebApp != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+
+	if set > 1 {
+		return fmt.Errorf("button %q must have at most one of the optional fields set, got %d", b.Text, set)
+	}
+
+	return nil
+}
+
+func validatePlaceholder(placeholder string) error {
+	if utf8.RuneCountInString(placeholder) > placeholderLimit {
+		return fmt.Errorf("input field placeholder exceeds %d characters", placeholderLimit)
+	}
+
+	return nil
+}
+
+// ReplyKeyboardRemove removes the reply keyboard of the user.
+// Doc https://core.telegram.org/bots/api#replykeyboardremove
+type ReplyKeyboardRemove struct {
+	// Selective removes the keyboard only for the users mentioned in the text of
+	// the message and the sender of the message the bot replies to.
+	Selective bool `json:"selective,omitempty"`
+}
+
+// replyKeyboardRemove is encoded along with the remove_keyboard field, which is always true.
+type replyKeyboardRemove ReplyKeyboardRemove
+
+func NewReplyKeyboardRemove(selective bool) *ReplyKeyboardRemove {
+	return &ReplyKeyboardRemove{Selective: selective}
+}
+
+func (m *ReplyKeyboardRemove) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RemoveKeyboard bool `json:"remove_keyboard"`
+		replyKeyboardRemove
+	}{true, replyKeyboardRemove(*m)})
+}
+
+// ForceReply shows the reply interface to the user, as if they selected the
+// message of the bot and tapped Reply.
+// Doc https://core.telegram.org/bots/api#forcereply
+type ForceReply struct {
+	InputFieldPlaceholder string `json:"input_field_placeholder,omitempty"`
+	// Selective forces the reply only for the users mentioned in the text of
+	// the message and the sender of the message the bot replies to.
+	Selective bool `json:"selective,omitempty"`
+}
+
+// forceReply is encoded along with the force_reply field, which is always true.
+type forceReply ForceReply
+
+func NewForceReply(placeholder string, selective bool) *ForceReply {
+	return &ForceReply{InputFieldPlaceholder: placeholder, Selective: selective}
+}
+
+func (m *ForceReply) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ForceReply bool `json:"force_reply"`
+		forceReply
+	}{true, forceReply(*m)})
+}
+
+func (m *ForceReply) Validate() error {
+	return validatePlaceholder(m.InputFieldPlaceholder)
+}
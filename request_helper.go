@@ -1,46 +1,93 @@
 package botty
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"reflect"
 	"strings"
 	"unicode/utf8"
 )
 
-func addEntitiesToRequest(v url.Values, name, text string, entities []MessageEntity) error {
-	if len(entities) > 0 {
-		for i := range entities {
-			if entities[i].Offset == 0 {
-				entities[i].Length = utf8.RuneCountInString(strings.TrimSpace(text))
-			}
+func prepareEntities(text string, entities []MessageEntity) []MessageEntity {
+	for i := range entities {
+		if entities[i].Offset == 0 {
+			entities[i].Length = utf8.RuneCountInString(strings.TrimSpace(text))
 		}
+	}
+
+	return entities
+}
+
+// encodeReplyMarkup validates and encodes the markup, the callback data
+// exceeding 64 bytes is moved to the callback store if there is one.
+func (c *Client) encodeReplyMarkup(ctx context.Context, markup ReplyMarkup) (json.RawMessage, error) {
+	// a nil pointer to a markup type means no markup as well
+	if v := reflect.ValueOf(markup); markup == nil || v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, nil
+	}
 
-		serializedEntities, err := json.Marshal(entities)
-		if err != nil {
-			return fmt.Errorf("can't sent message, %w", err)
+	if v, ok := markup.(ReplyMarkupValidator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid reply markup, %w", err)
 		}
+	}
+
+	if m, ok := markup.(*InlineKeyboardMarkup); ok {
+		if c.callbackStore != nil {
+			stored, err := c.storeCallbackData(ctx, m)
+			if err != nil {
+				return nil, err
+			}
 
-		v.Add(name, string(serializedEntities))
+			markup = stored
+		} else if err := checkCallbackDataSize(m); err != nil {
+			return nil, fmt.Errorf("invalid reply markup, %w", err)
+		}
 	}
 
-	return nil
+	data, err := markup.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("can't encode reply markup, %w", err)
+	}
+
+	return data, nil
 }
 
-func addEntitiesToForm(f MultipartForm, name, text string, entities []MessageEntity) error {
-	if len(entities) > 0 {
-		for i := range entities {
-			if entities[i].Offset == 0 {
-				entities[i].Length = utf8.RuneCountInString(strings.TrimSpace(text))
+func checkCallbackDataSize(m *InlineKeyboardMarkup) error {
+	for _, row := range m.InlineKeyboardMarkup {
+		for _, b := range row {
+			if len(b.CallbackData) > callbackDataLimit {
+				return fmt.Errorf("button %q, %w", b.Text, ErrCallbackDataTooLong)
 			}
 		}
+	}
+
+	return nil
+}
+
+// addFieldsToForm adds the fields of the JSON encoded params to the form.
+// Strings are added as is, other values are added JSON encoded.
+func addFieldsToForm(f MultipartForm, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for name, value := range fields {
+		var s string
 
-		serializedEntities, err := json.Marshal(entities)
-		if err != nil {
-			return fmt.Errorf("can't sent message, %w", err)
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
 		}
 
-		if err := f.AddField(name, string(serializedEntities)); err != nil {
+		if err := f.AddField(name, s); err != nil {
 			return err
 		}
 	}
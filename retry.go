@@ -0,0 +1,184 @@
+package botty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// idempotentMethods are retried after server and network errors even if
+// RetryPolicy.RetrySends is not set, repeating them has no visible effect.
+var idempotentMethods = map[string]bool{
+	methodGetUpdates:     true,
+	methodGetMe:          true,
+	methodGetFile:        true,
+	methodSetWebhook:     true,
+	methodDeleteWebhook:  true,
+	methodGetWebhookInfo: true,
+}
+
+// Backoffs used when RetryPolicy leaves them unset.
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy describes how failed requests are repeated.
+//
+// Requests rejected with 429 Too Many Requests are always retried, waiting
+// exactly as long as Telegram asks to. Requests failed with a 5xx code or a
+// network error are retried using an exponential backoff with jitter, but only
+// for idempotent methods, unless RetrySends is set: Telegram may have already
+// delivered the message, so a repeated send can duplicate it. Edits aren't
+// idempotent either: a repeated edit fails with "message is not modified".
+//
+// A zero MinBackoff or MaxBackoff is replaced by 500ms or 30s respectively.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	RetrySends  bool
+}
+
+// WithRetryPolicy enables retries of failed requests. By default every request is sent once.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = defaultMinBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	if policy.MaxBackoff < policy.MinBackoff {
+		policy.MaxBackoff = policy.MinBackoff
+	}
+
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	// a random delay from the upper half of the interval keeps clients from retrying in sync
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// do executes the request created by newRequest and returns the response body,
+// repeating the request according to the retry policy. Requests sent to a chat
+// wait for the rate limiter before every attempt.
+func (c *Client) do(ctx context.Context, method string, chatID int, newRequest func() (*http.Request, error)) ([]byte, error) {
+	maxAttempts := 1
+	if c.retryPolicy != nil && c.retryPolicy.MaxAttempts > 1 {
+		maxAttempts = c.retryPolicy.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.rateLimiter != nil && chatID != 0 {
+			if err := c.rateLimiter.Wait(ctx, chatID); err != nil {
+				return nil, fmt.Errorf("can't wait for rate limiter, %w", err)
+			}
+		}
+
+		body, status, err := c.exec(newRequest)
+		if attempt == maxAttempts {
+			if err != nil {
+				return nil, err
+			}
+			return checkResponse(body, status)
+		}
+
+		var delay time.Duration
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !c.canRetry(method) {
+				return nil, err
+			}
+			delay = c.retryPolicy.backoff(attempt)
+		case status == http.StatusTooManyRequests:
+			delay = retryAfter(body)
+			if delay == 0 {
+				delay = c.retryPolicy.backoff(attempt)
+			}
+		case status >= http.StatusInternalServerError:
+			if !c.canRetry(method) {
+				return checkResponse(body, status)
+			}
+			delay = c.retryPolicy.backoff(attempt)
+		default:
+			return checkResponse(body, status)
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				return checkResponse(body, status)
+			}
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) exec(newRequest func() (*http.Request, error)) ([]byte, int, error) {
+	req, err := newRequest()
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't create request, %w", err)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't exec request, %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't read response, %w", err)
+	}
+
+	return body, res.StatusCode, nil
+}
+
+// checkResponse turns a failed response that isn't a Bot API response, e.g. an
+// HTML page of a proxy, into an APIError with the HTTP status code.
+func checkResponse(body []byte, status int) ([]byte, error) {
+	if status >= http.StatusOK && status < http.StatusMultipleChoices {
+		return body, nil
+	}
+
+	var res ResultResponse
+
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, newAPIError(status, http.StatusText(status), nil)
+	}
+
+	return body, nil
+}
+
+func (c *Client) canRetry(method string) bool {
+	return idempotentMethods[method] || c.retryPolicy.RetrySends
+}
+
+func retryAfter(body []byte) time.Duration {
+	res := new(ResultResponse)
+	if err := json.Unmarshal(body, res); err != nil || res.Parameters == nil {
+		return 0
+	}
+
+	return time.Duration(res.Parameters.RetryAfter) * time.Second
+}
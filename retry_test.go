@@ -0,0 +1,41 @@
+package botty
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFailedResponseWithoutAPIBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "proxy page", status: http.StatusBadGateway, body: "bad gateway"},
+		{name: "html page", status: http.StatusServiceUnavailable, body: "<html>maintenance</html>"},
+		{name: "empty body", status: http.StatusNotFound, body: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, tt.body, tt.status)
+			}))
+			defer srv.Close()
+
+			c := NewClient("token", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+
+			_, err := c.SendMessage(&MessageData{ChatID: 1, Text: "hello"})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("SendMessage() error = %v, want an APIError", err)
+			}
+			if apiErr.ErrorCode != tt.status {
+				t.Errorf("ErrorCode = %d, want %d", apiErr.ErrorCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package botty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// wildcard is the route key matching any message or callback query.
+const wildcard = "*"
+
+// Routes are tried in the order of their priority, routes with the same
+// priority are tried in the order they were registered:
+//
+//  1. commands registered by OnCommand;
+//  2. text messages matched by OnMessage, OnMessageIgnoreCase, OnMessagePrefix,
+//     OnMessageContains, OnMessageRegex and OnMessageFunc, in this order;
+//  3. messages matched by the content type, e.g. OnPhoto;
+//  4. callback queries matched by OnQuery;
+//  5. the other update kinds, e.g. OnEditedMessage or OnChatMember;
+//  6. the "*" wildcards of OnMessage and OnQuery.
+//
+// The first matching route handles the update, unless its handler returns
+// ErrContinue. The fallback handler handles updates no route handled.
+const (
+	priorityCommand = iota
+	priorityMessage
+	priorityIgnoreCase
+	priorityPrefix
+	priorityContains
+	priorityRegex
+	priorityFunc
+	priorityContent
+	priorityQuery
+	priorityUpdate
+	priorityWildcard
+)
+
+// ErrContinue is returned by a handler to pass the update to the next matching route.
+var ErrContinue = errors.New("continue to the next route")
+
+type route struct {
+	priority int
+	match    func(ctx *Context) bool
+	handler  HandlerFunc
+}
+
+func (c *Client) addRoute(priority int, match func(ctx *Context) bool, f HandlerFunc, middleware []Middleware) {
+	c.routes = append(c.routes, route{
+		priority: priority,
+		match:    match,
+		handler:  chain(f, middleware),
+	})
+
+	sort.SliceStable(c.routes, func(i, j int) bool {
+		return c.routes[i].priority < c.routes[j].priority
+	})
+}
+
+func (c *Client) OnCommands(commands []string, f HandlerFunc, middleware ...Middleware) {
+	for _, cmd := range commands {
+		c.OnCommand(cmd, f, middleware...)
+	}
+}
+
+func (c *Client) OnCommand(cmd string, f HandlerFunc, middleware ...Middleware) {
+	cmd = strings.ToLower(cmd)
+
+	c.addRoute(priorityCommand, func(ctx *Context) bool {
+		return ctx.command.name == cmd
+	}, f, middleware)
+}
+
+func (c *Client) OnMessages(messages []string, f HandlerFunc, middleware ...Middleware) {
+	for _, msg := range messages {
+		c.OnMessage(msg, f, middleware...)
+	}
+}
+
+// OnMessage handles text messages equal to msg, the "*" wildcard handles any
+// text message no other route handled.
+func (c *Client) OnMessage(msg string, f HandlerFunc, middleware ...Middleware) {
+	if msg == wildcard {
+		c.addRoute(priorityWildcard, func(ctx *Context) bool {
+			return ctx.isTextMessage()
+		}, f, middleware)
+
+		return
+	}
+
+	c.addMessageRoute(priorityMessage, func(_ *Context, text string) bool {
+		return text == msg
+	}, f, middleware)
+}
+
+// OnQuery handles callback queries of the buttons with the query unique key,
+// the "*" wildcard handles any callback query no other route handled.
+func (c *Client) OnQuery(query string, f HandlerFunc, middleware ...Middleware) {
+	if query == wildcard {
+		c.addRoute(priorityWildcard, func(ctx *Context) bool {
+			return ctx.update.CallbackQuery != nil
+		}, f, middleware)
+
+		return
+	}
+
+	c.addRoute(priorityQuery, func(ctx *Context) bool {
+		return ctx.update.CallbackQuery != nil && ctx.query == query
+	}, f, middleware)
+}
+
+func (c *Client) OnEditedMessage(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.EditedMessage != nil }, f, middleware)
+}
+
+func (c *Client) OnChannelPost(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.ChannelPost != nil }, f, middleware)
+}
+
+func (c *Client) OnEditedChannelPost(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.EditedChannelPost != nil }, f, middleware)
+}
+
+func (c *Client) OnInlineQuery(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.InlineQuery != nil }, f, middleware)
+}
+
+func (c *Client) OnChosenInlineResult(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.ChosenInlineResult != nil }, f, middleware)
+}
+
+func (c *Client) OnShippingQuery(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.ShippingQuery != nil }, f, middleware)
+}
+
+func (c *Client) OnPreCheckoutQuery(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.PreCheckoutQuery != nil }, f, middleware)
+}
+
+func (c *Client) OnPoll(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.Poll != nil }, f, middleware)
+}
+
+func (c *Client) OnPollAnswer(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.PollAnswer != nil }, f, middleware)
+}
+
+// OnMyChatMember handles changes of the bot member status, e.g. the bot was blocked or added to a group.
+func (c *Client) OnMyChatMember(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.MyChatMember != nil }, f, middleware)
+}
+
+// OnChatMember handles changes of the chat member status. The bot must be an
+// administrator and request the chat_member updates using WithAllowedUpdates.
+func (c *Client) OnChatMember(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.ChatMember != nil }, f, middleware)
+}
+
+func (c *Client) OnChatJoinRequest(f HandlerFunc, middleware ...Middleware) {
+	c.onUpdate(func(u *Update) bool { return u.ChatJoinRequest != nil }, f, middleware)
+}
+
+func (c *Client) onUpdate(match func(u *Update) bool, f HandlerFunc, middleware []Middleware) {
+	c.addRoute(priorityUpdate, func(ctx *Context) bool {
+		return match(&ctx.update)
+	}, f, middleware)
+}
+
+// Fallback handles updates no route handled.
+func (c *Client) Fallback(f HandlerFunc, middleware ...Middleware) {
+	c.fallback = chain(f, middleware)
+}
+
+func (c *Client) addMessageRoute(priority int, match func(ctx *Context, text string) bool, f HandlerFunc, middleware []Middleware) {
+	c.addRoute(priority, func(ctx *Context) bool {
+		return ctx.isTextMessage() && match(ctx, strings.TrimSpace(ctx.update.Message.Text))
+	}, f, middleware)
+}
+
+func (c *Client) processUpdate(ctx context.Context, u Update) error {
+	uctx := newContext(ctx, c, u)
+
+	if q := u.CallbackQuery; q != nil {
+		c.queries.start(q.ID)
+	}
+
+	err := c.dispatch(uctx)
+
+	// the handlers could have answered the query using Context.Answer or AnswerCallbackQuery
+	answered := u.CallbackQuery != nil && c.queries.finish(u.CallbackQuery.ID)
+
+	if err != nil {
+		return fmt.Errorf("can't process update %d, %w", u.UpdateID, err)
+	}
+
+	if u.CallbackQuery != nil && !answered {
+		return c.replyToQuery(ctx, u)
+	}
+
+	return nil
+}
+
+// dispatch passes the update to the first matching route.
+func (c *Client) dispatch(ctx *Context) error {
+	if ctx.update.hasMessageText() {
+		if cmd, ok := parseCommand(ctx.update.Message); ok {
+			forBot, err := c.isForBot(ctx.ctx, cmd)
+			if err != nil {
+				return err
+			}
+			if !forBot {
+				return nil
+			}
+
+			ctx.command = cmd
+		}
+	}
+
+	if q := ctx.update.CallbackQuery; q != nil && q.Data != "" {
+		if err := c.loadCallbackData(ctx.ctx, q); err != nil {
+			return err
+		}
+
+		ctx.queryData = q.Data
+		ctx.query, q.Data, _ = strings.Cut(q.Data, "|")
+	}
+
+	for _, r := range c.routes {
+		if !r.match(ctx) {
+			continue
+		}
+
+		if err := c.handle(r.handler, ctx); !errors.Is(err, ErrContinue) {
+			return err
+		}
+	}
+
+	if c.fallback != nil {
+		if err := c.handle(c.fallback, ctx); !errors.Is(err, ErrContinue) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type SendAnimationData struct {
+	ChatID                   int
+	MessageThreadID          int
+	ReplyToMessageID         int
+	Animation                InputFile
+	Thumbnail                InputFile
+	Caption                  string
+	ParseMode                string
+	Duration                 int
+	Width                    int
+	Height                   int
+	HasSpoiler               bool
+	DisableNotification      bool
+	ProtectContent           bool
+	AllowSendingWithoutReply bool
+	CaptionEntities          []MessageEntity
+	ReplyMarkup              ReplyMarkup
+}
+
+type sendAnimationRequest struct {
+	sendMediaRequest
+	Duration   int  `json:"duration,omitempty"`
+	Width      int  `json:"width,omitempty"`
+	Height     int  `json:"height,omitempty"`
+	HasSpoiler bool `json:"has_spoiler,omitempty"`
+}
+
+func (d *SendAnimationData) validate() error {
+	if d.ChatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if d.Animation.isZero() {
+		return fmt.Errorf("animation is required")
+	}
+
+	return validateThumbnail(d.Thumbnail)
+}
+
+func (d *SendAnimationData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		Caption:                  d.Caption,
+		ParseMode:                d.ParseMode,
+		CaptionEntities:          d.CaptionEntities,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
+	}
+}
+
+// SendAnimation sends a GIF or an H.264/MPEG-4 AVC video without sound.
+func (c *Client) SendAnimation(d *SendAnimationData) (*Message, error) {
+	return c.SendAnimationContext(context.Background(), d)
+}
+
+func (c *Client) SendAnimationContext(ctx context.Context, d *SendAnimationData) (_ *Message, err error) {
+	defer func() { err = wrapIfErr("can't send animation", err) }()
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &sendAnimationRequest{
+		sendMediaRequest: media,
+		Duration:         d.Duration,
+		Width:            d.Width,
+		Height:           d.Height,
+		HasSpoiler:       d.HasSpoiler,
+	}
+
+	return c.sendMedia(ctx, methodSendAnimation, d.ChatID, req, map[string]InputFile{
+		"animation": d.Animation,
+		"thumbnail": d.Thumbnail,
+	})
+}
@@ -0,0 +1,89 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type SendAudioData struct {
+	ChatID                   int
+	MessageThreadID          int
+	ReplyToMessageID         int
+	Audio                    InputFile
+	Thumbnail                InputFile
+	Caption                  string
+	ParseMode                string
+	Duration                 int
+	Performer                string
+	Title                    string
+	DisableNotification      bool
+	ProtectContent           bool
+	AllowSendingWithoutReply bool
+	CaptionEntities          []MessageEntity
+	ReplyMarkup              ReplyMarkup
+}
+
+type sendAudioRequest struct {
+	sendMediaRequest
+	Duration  int    `json:"duration,omitempty"`
+	Performer string `json:"performer,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+func (d *SendAudioData) validate() error {
+	if d.ChatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if d.Audio.isZero() {
+		return fmt.Errorf("audio is required")
+	}
+
+	return validateThumbnail(d.Thumbnail)
+}
+
+func (d *SendAudioData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		Caption:                  d.Caption,
+		ParseMode:                d.ParseMode,
+		CaptionEntities:          d.CaptionEntities,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
+	}
+}
+
+// SendAudio sends an audio file displayed in the music player, it must be in the MP3 or M4A format.
+// Use SendVoice to send voice messages.
+func (c *Client) SendAudio(d *SendAudioData) (*Message, error) {
+	return c.SendAudioContext(context.Background(), d)
+}
+
+func (c *Client) SendAudioContext(ctx context.Context, d *SendAudioData) (_ *Message, err error) {
+	defer func() { err = wrapIfErr("can't send audio", err) }()
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &sendAudioRequest{
+		sendMediaRequest: media,
+		Duration:         d.Duration,
+		Performer:        d.Performer,
+		Title:            d.Title,
+	}
+
+	return c.sendMedia(ctx, methodSendAudio, d.ChatID, req, map[string]InputFile{
+		"audio":     d.Audio,
+		"thumbnail": d.Thumbnail,
+	})
+}
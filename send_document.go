@@ -0,0 +1,82 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type SendDocumentData struct {
+	ChatID                      int
+	MessageThreadID             int
+	ReplyToMessageID            int
+	Document                    InputFile
+	Thumbnail                   InputFile
+	Caption                     string
+	ParseMode                   string
+	DisableContentTypeDetection bool
+	DisableNotification         bool
+	ProtectContent              bool
+	AllowSendingWithoutReply    bool
+	CaptionEntities             []MessageEntity
+	ReplyMarkup                 ReplyMarkup
+}
+
+type sendDocumentRequest struct {
+	sendMediaRequest
+	DisableContentTypeDetection bool `json:"disable_content_type_detection,omitempty"`
+}
+
+func (d *SendDocumentData) validate() error {
+	if d.ChatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if d.Document.isZero() {
+		return fmt.Errorf("document is required")
+	}
+
+	return validateThumbnail(d.Thumbnail)
+}
+
+func (d *SendDocumentData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		Caption:                  d.Caption,
+		ParseMode:                d.ParseMode,
+		CaptionEntities:          d.CaptionEntities,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
+	}
+}
+
+// SendDocument sends a general file, up to 50 MB.
+func (c *Client) SendDocument(d *SendDocumentData) (*Message, error) {
+	return c.SendDocumentContext(context.Background(), d)
+}
+
+func (c *Client) SendDocumentContext(ctx context.Context, d *SendDocumentData) (_ *Message, err error) {
+	defer func() { err = wrapIfErr("can't send document", err) }()
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &sendDocumentRequest{
+		sendMediaRequest:            media,
+		DisableContentTypeDetection: d.DisableContentTypeDetection,
+	}
+
+	return c.sendMedia(ctx, methodSendDocument, d.ChatID, req, map[string]InputFile{
+		"document":  d.Document,
+		"thumbnail": d.Thumbnail,
+	})
+}
@@ -1,9 +1,9 @@
 package botty
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"net/url"
-	"strconv"
 	"strings"
 )
 
@@ -21,6 +21,20 @@ type MessageData struct {
 	ReplyMarkup              ReplyMarkup
 }
 
+type sendMessageRequest struct {
+	ChatID                   int             `json:"chat_id"`
+	MessageThreadID          int             `json:"message_thread_id,omitempty"`
+	Text                     string          `json:"text"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	Entities                 []MessageEntity `json:"entities,omitempty"`
+	DisableWebPagePreview    bool            `json:"disable_web_page_preview,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int             `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              json.RawMessage `json:"reply_markup,omitempty"`
+}
+
 func (d *MessageData) validate() error {
 	if d.ChatID == 0 {
 		return fmt.Errorf("chat_id is required")
@@ -33,35 +47,39 @@ func (d *MessageData) validate() error {
 	return nil
 }
 
-func (c *Client) SendMessage(data *MessageData) (m *Message, err error) {
+func (c *Client) SendMessage(data *MessageData) (*Message, error) {
+	return c.SendMessageContext(context.Background(), data)
+}
+
+func (c *Client) SendMessageContext(ctx context.Context, data *MessageData) (m *Message, err error) {
 	defer func() { err = wrapIfErr("can't send message", err) }()
 
 	if err := data.validate(); err != nil {
 		return nil, err
 	}
 
-	v := url.Values{}
 	text := strings.TrimSpace(data.Text)
 
-	v.Add("chat_id", strconv.Itoa(data.ChatID))
-	v.Add("text", text)
-	v.Add("message_thread_id", strconv.Itoa(data.MessageThreadID))
-	v.Add("parse_mode", data.ParseMode)
-	v.Add("disable_web_page_preview", strconv.FormatBool(data.DisableWebPagePreview))
-	v.Add("disable_notification", strconv.FormatBool(data.DisableNotification))
-	v.Add("protect_content", strconv.FormatBool(data.ProtectContent))
-	v.Add("reply_to_message_id", strconv.Itoa(data.ReplyToMessageID))
-	v.Add("allow_sending_without_reply", strconv.FormatBool(data.AllowSendingWithoutReply))
-
-	if err := addEntitiesToRequest(v, "entities", text, data.Entities); err != nil {
+	markup, err := c.encodeReplyMarkup(ctx, data.ReplyMarkup)
+	if err != nil {
 		return nil, err
 	}
 
-	if data.ReplyMarkup != nil {
-		v.Add("reply_markup", data.ReplyMarkup.GetText())
+	req := &sendMessageRequest{
+		ChatID:                   data.ChatID,
+		MessageThreadID:          data.MessageThreadID,
+		Text:                     text,
+		ParseMode:                data.ParseMode,
+		Entities:                 prepareEntities(text, data.Entities),
+		DisableWebPagePreview:    data.DisableWebPagePreview,
+		DisableNotification:      data.DisableNotification,
+		ProtectContent:           data.ProtectContent,
+		ReplyToMessageID:         data.ReplyToMessageID,
+		AllowSendingWithoutReply: data.AllowSendingWithoutReply,
+		ReplyMarkup:              markup,
 	}
 
-	res, err := c.doRequest(methodSendMessage, v)
+	res, err := c.doRequest(ctx, methodSendMessage, data.ChatID, req)
 	if err != nil {
 		return nil, fmt.Errorf("can't send message, %w", err)
 	}
@@ -1,19 +1,18 @@
 package botty
 
 import (
+	"context"
 	"fmt"
-	"net/url"
-	"os"
-	"strconv"
 )
 
 type SendPhotoData struct {
 	ChatID                   int
 	MessageThreadID          int
 	ReplyToMessageID         int
-	Photo                    string
+	Photo                    InputFile
 	Caption                  string
 	ParseMode                string
+	HasSpoiler               bool
 	DisableNotification      bool
 	ProtectContent           bool
 	AllowSendingWithoutReply bool
@@ -21,128 +20,60 @@ type SendPhotoData struct {
 	ReplyMarkup              ReplyMarkup
 }
 
+type sendPhotoRequest struct {
+	sendMediaRequest
+	HasSpoiler bool `json:"has_spoiler,omitempty"`
+}
+
 func (d *SendPhotoData) validate() (err error) {
 	if d.ChatID == 0 {
 		return fmt.Errorf("chat_id is required")
 	}
 
-	if d.Photo == "" {
+	if d.Photo.isZero() {
 		return fmt.Errorf("photo is required")
 	}
 
 	return nil
 }
 
-func (d *SendPhotoData) isLocalFilePath() bool {
-	uri, _ := url.ParseRequestURI(d.Photo)
-	if uri.Host == "" {
-		return true
+func (d *SendPhotoData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		Caption:                  d.Caption,
+		ParseMode:                d.ParseMode,
+		CaptionEntities:          d.CaptionEntities,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
 	}
+}
 
-	return false
+func (c *Client) SendPhoto(d *SendPhotoData) (*Message, error) {
+	return c.SendPhotoContext(context.Background(), d)
 }
 
-func (c *Client) SendPhoto(d *SendPhotoData) (_ *Message, err error) {
+func (c *Client) SendPhotoContext(ctx context.Context, d *SendPhotoData) (_ *Message, err error) {
 	defer func() { err = wrapIfErr("can't send photo", err) }()
 
 	if err := d.validate(); err != nil {
 		return nil, err
 	}
 
-	if d.isLocalFilePath() {
-		return c.sendLocalFile(d)
-	}
-
-	return c.sendRemoteFile(d)
-}
-
-func (c *Client) sendLocalFile(d *SendPhotoData) (*Message, error) {
-	f, err := os.Open(d.Photo)
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
 	if err != nil {
 		return nil, err
 	}
 
-	form := NewMultipartForm()
-
-	if err = form.AddField("chat_id", strconv.Itoa(d.ChatID)); err != nil {
-		return nil, err
-	}
-
-	if err = form.AddField("message_thread_id", strconv.Itoa(d.MessageThreadID)); err != nil {
-		return nil, err
-	}
-
-	if err := form.AddField("caption", d.Caption); err != nil {
-		return nil, err
-	}
-
-	if err := form.AddField("parse_mode", d.ParseMode); err != nil {
-		return nil, err
-	}
-
-	if err := addEntitiesToForm(form, "caption_entities", d.Caption, d.CaptionEntities); err != nil {
-		return nil, err
-	}
-
-	if err = form.AddField("disable_notification", strconv.FormatBool(d.DisableNotification)); err != nil {
-		return nil, err
-	}
-
-	if err = form.AddField("protect_content", strconv.FormatBool(d.ProtectContent)); err != nil {
-		return nil, err
-	}
-
-	if err = form.AddField("reply_to_message_id", strconv.Itoa(d.ReplyToMessageID)); err != nil {
-		return nil, err
-	}
-
-	if err = form.AddField("allow_sending_without_reply", strconv.FormatBool(d.AllowSendingWithoutReply)); err != nil {
-		return nil, err
-	}
-
-	if d.ReplyMarkup != nil {
-		if err = form.AddField("reply_markup", d.ReplyMarkup.GetText()); err != nil {
-			return nil, err
-		}
-	}
-
-	if err = form.AddFile("photo", f); err != nil {
-		return nil, err
-	}
-
-	res, err := c.doMultipartFormRequest(methodSendPhoto, form)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.processResponse(res)
-}
-
-func (c *Client) sendRemoteFile(d *SendPhotoData) (*Message, error) {
-	v := url.Values{}
-
-	v.Add("chat_id", strconv.Itoa(d.ChatID))
-	v.Add("photo", d.Photo)
-	v.Add("message_thread_id", strconv.Itoa(d.MessageThreadID))
-	v.Add("caption", d.Caption)
-	v.Add("parse_mode", d.ParseMode)
-	v.Add("disable_notification", strconv.FormatBool(d.DisableNotification))
-	v.Add("protect_content", strconv.FormatBool(d.ProtectContent))
-	v.Add("reply_to_message_id", strconv.Itoa(d.ReplyToMessageID))
-	v.Add("allow_sending_without_reply", strconv.FormatBool(d.AllowSendingWithoutReply))
-
-	if d.ReplyMarkup != nil {
-		v.Add("reply_markup", d.ReplyMarkup.GetText())
-	}
-
-	if err := addEntitiesToRequest(v, "caption_entities", d.Caption, d.CaptionEntities); err != nil {
-		return nil, err
-	}
-
-	res, err := c.doRequest(methodSendPhoto, v)
-	if err != nil {
-		return nil, err
+	req := &sendPhotoRequest{
+		sendMediaRequest: media,
+		HasSpoiler:       d.HasSpoiler,
 	}
 
-	return c.processResponse(res)
+	return c.sendMedia(ctx, methodSendPhoto, d.ChatID, req, map[string]InputFile{
+		"photo": d.Photo,
+	})
 }
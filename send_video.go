@@ -0,0 +1,94 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type SendVideoData struct {
+	ChatID                   int
+	MessageThreadID          int
+	ReplyToMessageID         int
+	Video                    InputFile
+	Thumbnail                InputFile
+	Caption                  string
+	ParseMode                string
+	Duration                 int
+	Width                    int
+	Height                   int
+	HasSpoiler               bool
+	SupportsStreaming        bool
+	DisableNotification      bool
+	ProtectContent           bool
+	AllowSendingWithoutReply bool
+	CaptionEntities          []MessageEntity
+	ReplyMarkup              ReplyMarkup
+}
+
+type sendVideoRequest struct {
+	sendMediaRequest
+	Duration          int  `json:"duration,omitempty"`
+	Width             int  `json:"width,omitempty"`
+	Height            int  `json:"height,omitempty"`
+	HasSpoiler        bool `json:"has_spoiler,omitempty"`
+	SupportsStreaming bool `json:"supports_streaming,omitempty"`
+}
+
+func (d *SendVideoData) validate() error {
+	if d.ChatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if d.Video.isZero() {
+		return fmt.Errorf("video is required")
+	}
+
+	return validateThumbnail(d.Thumbnail)
+}
+
+func (d *SendVideoData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		Caption:                  d.Caption,
+		ParseMode:                d.ParseMode,
+		CaptionEntities:          d.CaptionEntities,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
+	}
+}
+
+// SendVideo sends an MPEG4 video, other formats may be sent using SendDocument.
+func (c *Client) SendVideo(d *SendVideoData) (*Message, error) {
+	return c.SendVideoContext(context.Background(), d)
+}
+
+func (c *Client) SendVideoContext(ctx context.Context, d *SendVideoData) (_ *Message, err error) {
+	defer func() { err = wrapIfErr("can't send video", err) }()
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &sendVideoRequest{
+		sendMediaRequest:  media,
+		Duration:          d.Duration,
+		Width:             d.Width,
+		Height:            d.Height,
+		HasSpoiler:        d.HasSpoiler,
+		SupportsStreaming: d.SupportsStreaming,
+	}
+
+	return c.sendMedia(ctx, methodSendVideo, d.ChatID, req, map[string]InputFile{
+		"video":     d.Video,
+		"thumbnail": d.Thumbnail,
+	})
+}
@@ -0,0 +1,80 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type SendVideoNoteData struct {
+	ChatID                   int
+	MessageThreadID          int
+	ReplyToMessageID         int
+	VideoNote                InputFile
+	Thumbnail                InputFile
+	Duration                 int
+	Length                   int
+	DisableNotification      bool
+	ProtectContent           bool
+	AllowSendingWithoutReply bool
+	ReplyMarkup              ReplyMarkup
+}
+
+type sendVideoNoteRequest struct {
+	sendMediaRequest
+	Duration int `json:"duration,omitempty"`
+	Length   int `json:"length,omitempty"`
+}
+
+func (d *SendVideoNoteData) validate() error {
+	if d.ChatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if d.VideoNote.isZero() {
+		return fmt.Errorf("video_note is required")
+	}
+
+	return validateThumbnail(d.Thumbnail)
+}
+
+func (d *SendVideoNoteData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
+	}
+}
+
+// SendVideoNote sends a rounded square MPEG4 video of up to 1 minute. Length is
+// the diameter of the video.
+func (c *Client) SendVideoNote(d *SendVideoNoteData) (*Message, error) {
+	return c.SendVideoNoteContext(context.Background(), d)
+}
+
+func (c *Client) SendVideoNoteContext(ctx context.Context, d *SendVideoNoteData) (_ *Message, err error) {
+	defer func() { err = wrapIfErr("can't send video note", err) }()
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &sendVideoNoteRequest{
+		sendMediaRequest: media,
+		Duration:         d.Duration,
+		Length:           d.Length,
+	}
+
+	return c.sendMedia(ctx, methodSendVideoNote, d.ChatID, req, map[string]InputFile{
+		"video_note": d.VideoNote,
+		"thumbnail":  d.Thumbnail,
+	})
+}
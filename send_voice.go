@@ -0,0 +1,81 @@
+package botty
+
+import (
+	"context"
+	"fmt"
+)
+
+type SendVoiceData struct {
+	ChatID                   int
+	MessageThreadID          int
+	ReplyToMessageID         int
+	Voice                    InputFile
+	Caption                  string
+	ParseMode                string
+	Duration                 int
+	DisableNotification      bool
+	ProtectContent           bool
+	AllowSendingWithoutReply bool
+	CaptionEntities          []MessageEntity
+	ReplyMarkup              ReplyMarkup
+}
+
+type sendVoiceRequest struct {
+	sendMediaRequest
+	Duration int `json:"duration,omitempty"`
+}
+
+func (d *SendVoiceData) validate() error {
+	if d.ChatID == 0 {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	if d.Voice.isZero() {
+		return fmt.Errorf("voice is required")
+	}
+
+	return nil
+}
+
+func (d *SendVoiceData) mediaOptions() mediaOptions {
+	return mediaOptions{
+		ChatID:                   d.ChatID,
+		MessageThreadID:          d.MessageThreadID,
+		ReplyToMessageID:         d.ReplyToMessageID,
+		Caption:                  d.Caption,
+		ParseMode:                d.ParseMode,
+		CaptionEntities:          d.CaptionEntities,
+		DisableNotification:      d.DisableNotification,
+		ProtectContent:           d.ProtectContent,
+		AllowSendingWithoutReply: d.AllowSendingWithoutReply,
+		ReplyMarkup:              d.ReplyMarkup,
+	}
+}
+
+// SendVoice sends a voice message, it must be in the OGG format encoded with OPUS,
+// or in the MP3 or M4A format.
+func (c *Client) SendVoice(d *SendVoiceData) (*Message, error) {
+	return c.SendVoiceContext(context.Background(), d)
+}
+
+func (c *Client) SendVoiceContext(ctx context.Context, d *SendVoiceData) (_ *Message, err error) {
+	defer func() { err = wrapIfErr("can't send voice", err) }()
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	media, err := c.newSendMediaRequest(ctx, d.mediaOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &sendVoiceRequest{
+		sendMediaRequest: media,
+		Duration:         d.Duration,
+	}
+
+	return c.sendMedia(ctx, methodSendVoice, d.ChatID, req, map[string]InputFile{
+		"voice": d.Voice,
+	})
+}
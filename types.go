@@ -1,5 +1,7 @@
 package botty
 
+import "encoding/json"
+
 type User struct {
 	ID                      int    `json:"id"`
 	ISBot                   bool   `json:"is_bot"`
@@ -40,12 +42,12 @@ type ChatPermissions struct {
 }
 
 type Location struct {
-	Longitude            float64
-	Latitude             float64
-	HorizontalAccuracy   float64
-	LivePeriod           int
-	Heading              int
-	ProximityAlertRadius int
+	Longitude            float64 `json:"longitude"`
+	Latitude             float64 `json:"latitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy"`
+	LivePeriod           int     `json:"live_period"`
+	Heading              int     `json:"heading"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius"`
 }
 
 type ChatLocation struct {
@@ -82,12 +84,145 @@ type Chat struct {
 	Location                           *ChatLocation    `json:"location"`
 }
 
+type PhotoSize struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int    `json:"file_size"`
+}
+
+type Animation struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumbnail"`
+	FileName     string     `json:"file_name"`
+	MimeType     string     `json:"mime_type"`
+	FileSize     int        `json:"file_size"`
+}
+
+type Audio struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Duration     int        `json:"duration"`
+	Performer    string     `json:"performer"`
+	Title        string     `json:"title"`
+	FileName     string     `json:"file_name"`
+	MimeType     string     `json:"mime_type"`
+	FileSize     int        `json:"file_size"`
+	Thumbnail    *PhotoSize `json:"thumbnail"`
+}
+
+type Document struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Thumbnail    *PhotoSize `json:"thumbnail"`
+	FileName     string     `json:"file_name"`
+	MimeType     string     `json:"mime_type"`
+	FileSize     int        `json:"file_size"`
+}
+
+type Sticker struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Type         string     `json:"type"` // Type of the sticker, currently one of “regular”, “mask”, “custom_emoji”
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	IsAnimated   bool       `json:"is_animated"`
+	IsVideo      bool       `json:"is_video"`
+	Thumbnail    *PhotoSize `json:"thumbnail"`
+	Emoji        string     `json:"emoji"`
+	SetName      string     `json:"set_name"`
+	FileSize     int        `json:"file_size"`
+}
+
+type Video struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumbnail"`
+	FileName     string     `json:"file_name"`
+	MimeType     string     `json:"mime_type"`
+	FileSize     int        `json:"file_size"`
+}
+
+type VideoNote struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Length       int        `json:"length"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumbnail"`
+	FileSize     int        `json:"file_size"`
+}
+
+type Voice struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"`
+	MimeType     string `json:"mime_type"`
+	FileSize     int    `json:"file_size"`
+}
+
+type Contact struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	UserID      int    `json:"user_id"`
+	VCard       string `json:"vcard"`
+}
+
+type Venue struct {
+	Location        *Location `json:"location"`
+	Title           string    `json:"title"`
+	Address         string    `json:"address"`
+	FoursquareID    string    `json:"foursquare_id"`
+	FoursquareType  string    `json:"foursquare_type"`
+	GooglePlaceID   string    `json:"google_place_id"`
+	GooglePlaceType string    `json:"google_place_type"`
+}
+
+// UserShared is the user picked by the button created by NewKeyboardButtonUser.
+type UserShared struct {
+	RequestID int `json:"request_id"`
+	UserID    int `json:"user_id"`
+}
+
+// ChatShared is the chat picked by the button created by NewKeyboardButtonChat.
+type ChatShared struct {
+	RequestID int `json:"request_id"`
+	ChatID    int `json:"chat_id"`
+}
+
 type Message struct {
 	MessageID       int             `json:"message_id"`
+	Date            int             `json:"date"`
 	Text            string          `json:"text"`
 	From            *User           `json:"from"`
+	SenderChat      *Chat           `json:"sender_chat"`
 	Chat            *Chat           `json:"chat"`
 	MessageEntities []MessageEntity `json:"entities"`
+	Animation       *Animation      `json:"animation"`
+	Audio           *Audio          `json:"audio"`
+	Document        *Document       `json:"document"`
+	Photo           []PhotoSize     `json:"photo"`
+	Sticker         *Sticker        `json:"sticker"`
+	Video           *Video          `json:"video"`
+	VideoNote       *VideoNote      `json:"video_note"`
+	Voice           *Voice          `json:"voice"`
+	Caption         string          `json:"caption"`
+	CaptionEntities []MessageEntity `json:"caption_entities"`
+	HasMediaSpoiler bool            `json:"has_media_spoiler"`
+	Contact         *Contact        `json:"contact"`
+	Poll            *Poll           `json:"poll"`
+	Venue           *Venue          `json:"venue"`
+	Location        *Location       `json:"location"`
+	UserShared      *UserShared     `json:"user_shared"`
+	ChatShared      *ChatShared     `json:"chat_shared"`
 }
 
 type CallbackQuery struct {
@@ -109,31 +244,283 @@ type InlineQuery struct {
 	Location *Location `json:"location"`
 }
 
+type ChosenInlineResult struct {
+	ResultID        string    `json:"result_id"`
+	From            *User     `json:"from"`
+	Location        *Location `json:"location"`
+	InlineMessageID string    `json:"inline_message_id"`
+	Query           string    `json:"query"`
+}
+
+type ShippingAddress struct {
+	CountryCode string `json:"country_code"`
+	State       string `json:"state"`
+	City        string `json:"city"`
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2"`
+	PostCode    string `json:"post_code"`
+}
+
+type ShippingQuery struct {
+	ID              string           `json:"id"`
+	From            *User            `json:"from"`
+	InvoicePayload  string           `json:"invoice_payload"`
+	ShippingAddress *ShippingAddress `json:"shipping_address"`
+}
+
+type OrderInfo struct {
+	Name            string           `json:"name"`
+	PhoneNumber     string           `json:"phone_number"`
+	Email           string           `json:"email"`
+	ShippingAddress *ShippingAddress `json:"shipping_address"`
+}
+
+type PreCheckoutQuery struct {
+	ID               string     `json:"id"`
+	From             *User      `json:"from"`
+	Currency         string     `json:"currency"`
+	TotalAmount      int        `json:"total_amount"`
+	InvoicePayload   string     `json:"invoice_payload"`
+	ShippingOptionID string     `json:"shipping_option_id"`
+	OrderInfo        *OrderInfo `json:"order_info"`
+}
+
+type PollOption struct {
+	Text       string `json:"text"`
+	VoterCount int    `json:"voter_count"`
+}
+
+type Poll struct {
+	ID                    string          `json:"id"`
+	Question              string          `json:"question"`
+	Options               []PollOption    `json:"options"`
+	TotalVoterCount       int             `json:"total_voter_count"`
+	IsClosed              bool            `json:"is_closed"`
+	IsAnonymous           bool            `json:"is_anonymous"`
+	Type                  string          `json:"type"` // Poll type, currently can be “regular” or “quiz”
+	AllowsMultipleAnswers bool            `json:"allows_multiple_answers"`
+	CorrectOptionID       int             `json:"correct_option_id"`
+	Explanation           string          `json:"explanation"`
+	ExplanationEntities   []MessageEntity `json:"explanation_entities"`
+	OpenPeriod            int             `json:"open_period"`
+	CloseDate             int             `json:"close_date"`
+}
+
+type PollAnswer struct {
+	PollID    string `json:"poll_id"`
+	VoterChat *Chat  `json:"voter_chat"`
+	User      *User  `json:"user"`
+	OptionIDs []int  `json:"option_ids"`
+}
+
+// ChatMember contains information about one member of a chat, the fields
+// available depend on the status.
+// Doc https://core.telegram.org/bots/api#chatmember
+type ChatMember struct {
+	Status                string `json:"status"` // Status of the member, can be “creator”, “administrator”, “member”, “restricted”, “left” or “kicked”
+	User                  *User  `json:"user"`
+	IsAnonymous           bool   `json:"is_anonymous"`
+	CustomTitle           string `json:"custom_title"`
+	UntilDate             int    `json:"until_date"`
+	IsMember              bool   `json:"is_member"`
+	CanBeEdited           bool   `json:"can_be_edited"`
+	CanManageChat         bool   `json:"can_manage_chat"`
+	CanDeleteMessages     bool   `json:"can_delete_messages"`
+	CanManageVideoChats   bool   `json:"can_manage_video_chats"`
+	CanRestrictMembers    bool   `json:"can_restrict_members"`
+	CanPromoteMembers     bool   `json:"can_promote_members"`
+	CanChangeInfo         bool   `json:"can_change_info"`
+	CanInviteUsers        bool   `json:"can_invite_users"`
+	CanPostMessages       bool   `json:"can_post_messages"`
+	CanEditMessages       bool   `json:"can_edit_messages"`
+	CanPinMessages        bool   `json:"can_pin_messages"`
+	CanManageTopics       bool   `json:"can_manage_topics"`
+	CanSendMessages       bool   `json:"can_send_messages"`
+	CanSendMediaMessages  bool   `json:"can_send_media_messages"`
+	CanSendPolls          bool   `json:"can_send_polls"`
+	CanSendOtherMessages  bool   `json:"can_send_other_messages"`
+	CanAddWebPagePreviews bool   `json:"can_add_web_page_previews"`
+}
+
+type ChatInviteLink struct {
+	InviteLink              string `json:"invite_link"`
+	Creator                 *User  `json:"creator"`
+	CreatesJoinRequest      bool   `json:"creates_join_request"`
+	IsPrimary               bool   `json:"is_primary"`
+	IsRevoked               bool   `json:"is_revoked"`
+	Name                    string `json:"name"`
+	ExpireDate              int    `json:"expire_date"`
+	MemberLimit             int    `json:"member_limit"`
+	PendingJoinRequestCount int    `json:"pending_join_request_count"`
+}
+
+type ChatMemberUpdated struct {
+	Chat                    *Chat           `json:"chat"`
+	From                    *User           `json:"from"`
+	Date                    int             `json:"date"`
+	OldChatMember           *ChatMember     `json:"old_chat_member"`
+	NewChatMember           *ChatMember     `json:"new_chat_member"`
+	InviteLink              *ChatInviteLink `json:"invite_link"`
+	ViaChatFolderInviteLink bool            `json:"via_chat_folder_invite_link"`
+}
+
+type ChatJoinRequest struct {
+	Chat       *Chat           `json:"chat"`
+	From       *User           `json:"from"`
+	UserChatID int             `json:"user_chat_id"`
+	Date       int             `json:"date"`
+	Bio        string          `json:"bio"`
+	InviteLink *ChatInviteLink `json:"invite_link"`
+}
+
 // Update represents data given from "getUpdates" query.
-// Doc https://core.telegram.org/bots/api#getting-updates TODO fill the structure
+// Doc https://core.telegram.org/bots/api#getting-updates
 type Update struct {
-	UpdateID          int            `json:"update_id"`
-	Message           *Message       `json:"message"`
-	EditedMessage     *Message       `json:"edited_message"`
-	ChannelPost       *Message       `json:"channel_post"`
-	InlineQuery       *InlineQuery   `json:"inline_query"`
-	EditedChannelPost *Message       `json:"edited_channel_post"`
-	CallbackQuery     *CallbackQuery `json:"callback_query"`
+	UpdateID           int                 `json:"update_id"`
+	Message            *Message            `json:"message"`
+	EditedMessage      *Message            `json:"edited_message"`
+	ChannelPost        *Message            `json:"channel_post"`
+	EditedChannelPost  *Message            `json:"edited_channel_post"`
+	InlineQuery        *InlineQuery        `json:"inline_query"`
+	ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result"`
+	CallbackQuery      *CallbackQuery      `json:"callback_query"`
+	ShippingQuery      *ShippingQuery      `json:"shipping_query"`
+	PreCheckoutQuery   *PreCheckoutQuery   `json:"pre_checkout_query"`
+	Poll               *Poll               `json:"poll"`
+	PollAnswer         *PollAnswer         `json:"poll_answer"`
+	MyChatMember       *ChatMemberUpdated  `json:"my_chat_member"`
+	ChatMember         *ChatMemberUpdated  `json:"chat_member"`
+	ChatJoinRequest    *ChatJoinRequest    `json:"chat_join_request"`
 }
 
 type UpdateResponse struct {
-	OK          bool     `json:"ok"`
-	Description string   `json:"description"`
-	Result      []Update `json:"result"`
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+	Result      []Update            `json:"result"`
 }
 
 type Response struct {
-	OK          bool     `json:"ok"`
-	ErrorCode   int      `json:"error_code"`
-	Description string   `json:"description"`
-	Result      *Message `json:"result"`
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+	Result      *Message            `json:"result"`
+}
+
+// ResultResponse is a response whose result is decoded separately, depending on the called method.
+type ResultResponse struct {
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+	Result      json.RawMessage     `json:"result"`
+}
+
+// File represents a file ready to be downloaded.
+// Doc https://core.telegram.org/bots/api#file
+type File struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int    `json:"file_size"`
+	FilePath     string `json:"file_path"`
+}
+
+// WebhookInfo describes the current status of a webhook.
+// Doc https://core.telegram.org/bots/api#webhookinfo
+type WebhookInfo struct {
+	URL                          string   `json:"url"`
+	HasCustomCertificate         bool     `json:"has_custom_certificate"`
+	PendingUpdateCount           int      `json:"pending_update_count"`
+	IPAddress                    string   `json:"ip_address"`
+	LastErrorDate                int      `json:"last_error_date"`
+	LastErrorMessage             string   `json:"last_error_message"`
+	LastSynchronizationErrorDate int      `json:"last_synchronization_error_date"`
+	MaxConnections               int      `json:"max_connections"`
+	AllowedUpdates               []string `json:"allowed_updates"`
 }
 
 func (u *Update) hasMessageText() bool {
 	return u.Message != nil && u.Message.Text != ""
 }
+
+// message returns the message of the update, or nil if there is none.
+func (u *Update) message() *Message {
+	switch {
+	case u.Message != nil:
+		return u.Message
+	case u.EditedMessage != nil:
+		return u.EditedMessage
+	case u.ChannelPost != nil:
+		return u.ChannelPost
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.Message
+	}
+
+	return nil
+}
+
+// chat returns the chat the update belongs to, or nil if there is none.
+func (u *Update) chat() *Chat {
+	switch {
+	case u.message() != nil:
+		return u.message().Chat
+	case u.MyChatMember != nil:
+		return u.MyChatMember.Chat
+	case u.ChatMember != nil:
+		return u.ChatMember.Chat
+	case u.ChatJoinRequest != nil:
+		return u.ChatJoinRequest.Chat
+	case u.PollAnswer != nil:
+		return u.PollAnswer.VoterChat
+	}
+
+	return nil
+}
+
+// sender returns the user who sent the update, or nil if there is none, e.g. for channel posts.
+func (u *Update) sender() *User {
+	switch {
+	case u.Message != nil:
+		return u.Message.From
+	case u.EditedMessage != nil:
+		return u.EditedMessage.From
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.InlineQuery != nil:
+		return u.InlineQuery.From
+	case u.ChosenInlineResult != nil:
+		return u.ChosenInlineResult.From
+	case u.ShippingQuery != nil:
+		return u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return u.PreCheckoutQuery.From
+	case u.PollAnswer != nil:
+		return u.PollAnswer.User
+	case u.MyChatMember != nil:
+		return u.MyChatMember.From
+	case u.ChatMember != nil:
+		return u.ChatMember.From
+	case u.ChatJoinRequest != nil:
+		return u.ChatJoinRequest.From
+	}
+
+	return nil
+}
+
+// chatKey returns a key of the chat the update belongs to. Updates without a
+// chat are keyed by the sender, or by their own ID when there is no sender either.
+func (u *Update) chatKey() uint64 {
+	if chat := u.chat(); chat != nil {
+		return uint64(chat.ID)
+	}
+
+	if sender := u.sender(); sender != nil {
+		return uint64(sender.ID)
+	}
+
+	return uint64(u.UpdateID)
+}
@@ -1,9 +1,9 @@
 package botty
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"net/url"
-	"strconv"
 	"strings"
 )
 
@@ -18,6 +18,17 @@ type UpdateMessageData struct {
 	ReplyMarkup           ReplyMarkup
 }
 
+type editMessageTextRequest struct {
+	ChatID                int             `json:"chat_id,omitempty"`
+	MessageID             int             `json:"message_id,omitempty"`
+	InlineMessageID       string          `json:"inline_message_id,omitempty"`
+	Text                  string          `json:"text"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity `json:"entities,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           json.RawMessage `json:"reply_markup,omitempty"`
+}
+
 func (t *UpdateMessageData) validate() error {
 	if t.InlineMessageID == "" && t.ChatID == 0 {
 		return fmt.Errorf("chat_id or inline_message_id is required")
@@ -38,32 +49,36 @@ func (t *UpdateMessageData) validate() error {
 	return nil
 }
 
-func (c *Client) UpdateMessage(data *UpdateMessageData) (m *Message, err error) {
+func (c *Client) UpdateMessage(data *UpdateMessageData) (*Message, error) {
+	return c.UpdateMessageContext(context.Background(), data)
+}
+
+func (c *Client) UpdateMessageContext(ctx context.Context, data *UpdateMessageData) (m *Message, err error) {
 	defer func() { err = wrapIfErr("can't update message", err) }()
 
 	if err := data.validate(); err != nil {
 		return nil, err
 	}
 
-	v := url.Values{}
 	text := strings.TrimSpace(data.Text)
 
-	v.Add("chat_id", strconv.Itoa(data.ChatID))
-	v.Add("message_id", strconv.Itoa(data.MessageID))
-	v.Add("text", text)
-	v.Add("inline_message_id", data.InlineMessageID)
-	v.Add("parse_mode", data.ParseMode)
-	v.Add("disable_web_page_preview", strconv.FormatBool(data.DisableWebPagePreview))
-
-	if err := addEntitiesToRequest(v, "entities", text, data.Entities); err != nil {
+	markup, err := c.encodeReplyMarkup(ctx, data.ReplyMarkup)
+	if err != nil {
 		return nil, err
 	}
 
-	if data.ReplyMarkup != nil {
-		v.Add("reply_markup", data.ReplyMarkup.GetText())
+	req := &editMessageTextRequest{
+		ChatID:                data.ChatID,
+		MessageID:             data.MessageID,
+		InlineMessageID:       data.InlineMessageID,
+		Text:                  text,
+		ParseMode:             data.ParseMode,
+		Entities:              prepareEntities(text, data.Entities),
+		DisableWebPagePreview: data.DisableWebPagePreview,
+		ReplyMarkup:           markup,
 	}
 
-	res, err := c.doRequest(methodEditMessageText, v)
+	res, err := c.doRequest(ctx, methodEditMessageText, data.ChatID, req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,234 @@
+package botty
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type WebhookData struct {
+	URL                string
+	Certificate        string
+	IPAddress          string
+	MaxConnections     int
+	AllowedUpdates     []string
+	DropPendingUpdates bool
+	SecretToken        string
+}
+
+type setWebhookRequest struct {
+	URL                string    `json:"url"`
+	IPAddress          string    `json:"ip_address,omitempty"`
+	MaxConnections     int       `json:"max_connections,omitempty"`
+	AllowedUpdates     *[]string `json:"allowed_updates,omitempty"`
+	DropPendingUpdates bool      `json:"drop_pending_updates,omitempty"`
+	SecretToken        string    `json:"secret_token,omitempty"`
+}
+
+type deleteWebhookRequest struct {
+	DropPendingUpdates bool `json:"drop_pending_updates,omitempty"`
+}
+
+func (d *WebhookData) validate() error {
+	if d.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	return nil
+}
+
+// WithWebhookSecretToken sets the secret token the webhook handler expects in the
+// X-Telegram-Bot-Api-Secret-Token header. It is also sent by SetWebhook unless
+// WebhookData.SecretToken is set.
+func WithWebhookSecretToken(token string) ClientOption {
+	return func(c *Client) {
+		c.webhookSecret = token
+	}
+}
+
+// ServeHTTP receives an update sent by Telegram to the webhook and dispatches it
+// to the registered handlers. Handler errors are reported to the error handler,
+// the update is acknowledged anyway so Telegram doesn't redeliver it.
+func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if c.webhookSecret != "" {
+		token := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.webhookSecret)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var u Update
+
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		c.handleError(fmt.Errorf("can't decode webhook update, %w", err))
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.processUpdate(r.Context(), u); err != nil {
+		c.handleError(err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RunWebhook serves the webhook handler on addr. The server uses TLS when both
+// certFile and keyFile are set, otherwise it serves plain HTTP, e.g. behind a load balancer.
+func (c *Client) RunWebhook(addr, certFile, keyFile string) error {
+	return c.RunWebhookContext(context.Background(), addr, certFile, keyFile)
+}
+
+// RunWebhookContext is like RunWebhook, but shuts the server down once ctx is
+// done, waiting up to the shutdown timeout for in-flight handlers.
+func (c *Client) RunWebhookContext(ctx context.Context, addr, certFile, keyFile string) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: c,
+	}
+
+	errc := make(chan error, 1)
+
+	go func() {
+		if certFile != "" && keyFile != "" {
+			errc <- srv.ListenAndServeTLS(certFile, keyFile)
+			return
+		}
+
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("can't shut down webhook server, %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) SetWebhook(d *WebhookData) error {
+	return c.SetWebhookContext(context.Background(), d)
+}
+
+func (c *Client) SetWebhookContext(ctx context.Context, d *WebhookData) (err error) {
+	defer func() { err = wrapIfErr("can't set webhook", err) }()
+
+	if err := d.validate(); err != nil {
+		return err
+	}
+
+	secretToken := d.SecretToken
+	if secretToken == "" {
+		secretToken = c.webhookSecret
+	}
+
+	allowedUpdates := d.AllowedUpdates
+	if allowedUpdates == nil {
+		allowedUpdates = c.allowedUpdates
+	}
+
+	req := &setWebhookRequest{
+		URL:                d.URL,
+		IPAddress:          d.IPAddress,
+		MaxConnections:     d.MaxConnections,
+		AllowedUpdates:     allowedUpdatesToRequest(allowedUpdates),
+		DropPendingUpdates: d.DropPendingUpdates,
+		SecretToken:        secretToken,
+	}
+
+	var res []byte
+
+	if d.Certificate != "" {
+		res, err = c.setWebhookWithCertificate(ctx, d.Certificate, req)
+	} else {
+		res, err = c.doRequest(ctx, methodSetWebhook, 0, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+
+	return c.decodeResult(res, &ok)
+}
+
+func (c *Client) setWebhookWithCertificate(ctx context.Context, certificate string, req *setWebhookRequest) ([]byte, error) {
+	f, err := os.Open(certificate)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	form := NewMultipartForm()
+
+	if err := addFieldsToForm(form, req); err != nil {
+		return nil, err
+	}
+
+	if err := form.AddFile("certificate", f); err != nil {
+		return nil, err
+	}
+
+	return c.doMultipartFormRequest(ctx, methodSetWebhook, 0, form)
+}
+
+func (c *Client) DeleteWebhook(dropPendingUpdates bool) error {
+	return c.DeleteWebhookContext(context.Background(), dropPendingUpdates)
+}
+
+func (c *Client) DeleteWebhookContext(ctx context.Context, dropPendingUpdates bool) (err error) {
+	defer func() { err = wrapIfErr("can't delete webhook", err) }()
+
+	req := &deleteWebhookRequest{
+		DropPendingUpdates: dropPendingUpdates,
+	}
+
+	res, err := c.doRequest(ctx, methodDeleteWebhook, 0, req)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+
+	return c.decodeResult(res, &ok)
+}
+
+func (c *Client) GetWebhookInfo() (*WebhookInfo, error) {
+	return c.GetWebhookInfoContext(context.Background())
+}
+
+func (c *Client) GetWebhookInfoContext(ctx context.Context) (_ *WebhookInfo, err error) {
+	defer func() { err = wrapIfErr("can't get webhook info", err) }()
+
+	res, err := c.doRequest(ctx, methodGetWebhookInfo, 0, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(WebhookInfo)
+
+	if err := c.decodeResult(res, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}